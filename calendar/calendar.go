@@ -8,9 +8,11 @@ package calendar
 import (
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,13 +20,13 @@ import (
 	"github.com/corpix/uarand"
 	"github.com/dnaeon/go-vcr/recorder"
 	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
 
 	"github.com/d3an/finviz/utils"
 )
 
 const (
 	APIURL = "https://finviz.com/calendar.ashx"
-	YEAR   = 2022
 )
 
 var (
@@ -71,6 +73,7 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return c.Client.Do(req)
 }
 
+// GetCalendar returns a DataFrame containing this week's economic calendar
 func (c *Client) GetCalendar() (*dataframe.DataFrame, error) {
 	req, err := http.NewRequest(http.MethodGet, APIURL, http.NoBody)
 	if err != nil {
@@ -97,7 +100,7 @@ func (c *Client) GetCalendar() (*dataframe.DataFrame, error) {
 		return nil, err
 	}
 
-	results, err := Scrape(doc)
+	results, err := Scrape(doc, utils.FetchTime(resp))
 	if err != nil {
 		return nil, err
 	}
@@ -106,40 +109,57 @@ func (c *Client) GetCalendar() (*dataframe.DataFrame, error) {
 	return &df, nil
 }
 
-func Scrape(doc *goquery.Document) ([][]string, error) {
+// emptyDataFrame returns a DataFrame with the provided columns and types, and no rows
+func emptyDataFrame(names []string, types []series.Type) (*dataframe.DataFrame, error) {
+	columns := make([]series.Series, len(names))
+	for i, name := range names {
+		columns[i] = series.New([]string{}, types[i], name)
+	}
+	empty := dataframe.New(columns...)
+	return &empty, empty.Error()
+}
+
+func Scrape(doc *goquery.Document, reference time.Time) ([][]string, error) {
 	var year int
 	var err error
 
-	year, err = strconv.Atoi(doc.Find(".copyright").Text()[89:93])
-	if err != nil {
-		year = YEAR
-	}
-
 	data := doc.Find("tr .calendar-header")
+	if data.Length() == 0 {
+		return nil, fmt.Errorf("error calendar day headers not found, the page layout may have changed")
+	}
 
 	var dow string
 	var calendarDataSlice []map[string]interface{}
 	data.Each(func(i int, day *goquery.Selection) {
-		dow = day.Children().Eq(0).Text()
+		if err != nil {
+			return
+		}
+		dow = strings.Join(strings.Fields(day.Children().Eq(0).Text()), " ")
+		if year, err = ResolveYear(dow, reference); err != nil {
+			return
+		}
 
 		day.Parent().Children().Each(func(j int, event *goquery.Selection) {
-			if j == 0 || len(event.Children().Nodes) < 9 {
+			if j == 0 || len(event.Children().Nodes) < 9 || err != nil {
 				return
 			}
 			var calendarEvent = make(map[string]interface{})
 			event.Children().Each(func(k int, eventDetails *goquery.Selection) {
 				switch k {
 				case 0:
-					date, err := time.Parse("Mon Jan 02 2006 3:04 PM MST", fmt.Sprintf("%s %d %s EST", dow, year, eventDetails.Text()))
-					if err != nil {
-						calendarEvent["Date"] = eventDetails.Text()
-						return
+					date, clockErr := utils.ParseCalendarDate(dow, eventDetails.Text(), year)
+					// Releases without a clock time, i.e. "Tentative" or "All Day", are dated at the start of the day
+					if clockErr != nil {
+						if date, err = utils.ParseCalendarDate(dow, "12:00 AM", year); err != nil {
+							return
+						}
 					}
 					calendarEvent["Date"] = date.Format(time.RFC3339)
 				case 1:
 					return
 				case 2:
 					calendarEvent["Release"] = eventDetails.Text()
+					calendarEvent["Group"] = GroupRelease(eventDetails.Text())
 				case 3:
 					switch eventDetails.Find("img").Eq(0).AttrOr("src", "") {
 					default:
@@ -161,10 +181,49 @@ func Scrape(doc *goquery.Document) ([][]string, error) {
 					calendarEvent["Prior"] = eventDetails.Text()
 				}
 			})
+			addValues(calendarEvent)
 			calendarDataSlice = append(calendarDataSlice, calendarEvent)
 		})
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	headers := []string{"Date", "Release", "Impact", "For", "Actual", "Expected", "Prior"}
+	headers := []string{
+		"Date", "Release", "Impact", "For", "Actual", "Expected", "Prior",
+		"Actual Value", "Expected Value", "Prior Value", "Unit", "Surprise", "Surprise %", "Group",
+	}
 	return utils.GenerateRows(headers, calendarDataSlice)
 }
+
+// addValues adds the parsed values, unit and surprise of an event's Actual, Expected and Prior columns. Values that
+// do not parse, i.e. ranges such as "0.25% - 0.50%", are missing, and only kept as raw text in their column.
+func addValues(calendarEvent map[string]interface{}) {
+	var values []Value
+	var unit Unit
+	for _, column := range []string{"Actual", "Expected", "Prior"} {
+		raw, _ := calendarEvent[column].(string)
+		value, err := ParseValue(raw)
+		if err != nil {
+			value = Value{Number: math.NaN()}
+		}
+		if unit == "" && !value.Missing() {
+			unit = value.Unit
+		}
+		calendarEvent[column+" Value"] = formatNumber(value.Number)
+		values = append(values, value)
+	}
+
+	difference, percent := Surprise(values[0], values[1])
+	calendarEvent["Unit"] = string(unit)
+	calendarEvent["Surprise"] = formatNumber(difference)
+	calendarEvent["Surprise %"] = formatNumber(percent)
+}
+
+// formatNumber formats a value for GenerateRows, rounding away floating point noise such as 3.1999999999999993
+func formatNumber(number float64) string {
+	if math.IsNaN(number) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(math.Round(number*1e6)/1e6, 'f', -1, 64)
+}
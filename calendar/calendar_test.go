@@ -6,13 +6,19 @@
 package calendar
 
 import (
+	"bytes"
+	"math"
 	"net"
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/corpix/uarand"
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
 	"github.com/stretchr/testify/require"
 
 	"github.com/d3an/finviz/utils"
@@ -56,3 +62,183 @@ func TestGetCalendar(t *testing.T) {
 		utils.PrintFullDataFrame(calendar)
 	}()
 }
+
+// calendarDay returns a calendar table of one day's releases, i.e. {"8:30 AM", "CPI", "Jul", "9.1%", "8.8%", "8.6%"}
+func calendarDay(day string, releases ...[]string) string {
+	table := `<table class="calendar"><tr class="calendar-header"><td><b>` + strings.Fields(day)[0] + `</b> ` +
+		strings.Join(strings.Fields(day)[1:], " ") + `</td><td></td><td>Release</td><td>Impact</td><td>For</td>` +
+		`<td>Actual</td><td>Expected</td><td>Prior</td><td></td></tr>`
+	for _, r := range releases {
+		table += `<tr><td>` + r[0] + `</td><td></td><td>` + r[1] + `</td><td><img src="gfx/calendar/impact_1.gif"></td>` +
+			`<td>` + r[2] + `</td><td>` + r[3] + `</td><td>` + r[4] + `</td><td>` + r[5] + `</td><td></td></tr>`
+	}
+	return table + `</table>`
+}
+
+func TestScrape(t *testing.T) {
+	doc, err := utils.GenerateDocument(`<html><body><table><tr><td>` + calendarDay("Wed Jul 13",
+		[]string{"8:30 AM", "CPI", "Jun", "9.1%", "8.8%", "8.6%"},
+		[]string{"Tentative", "Treasury Budget", "Jun", "", "-$70.0B", "-$66.2B"},
+		[]string{"2:00 PM", "FOMC Rate Decision", "Jul", "tentative", "0.25% - 0.50%", "1.75%"},
+	) + `</td></tr></table></body></html>`)
+	require.Nil(t, err)
+
+	rows, err := Scrape(doc, time.Date(2022, 7, 13, 12, 0, 0, 0, utils.Eastern))
+	require.Nil(t, err)
+	require.Len(t, rows, 4)
+	df := dataframe.LoadRecords(rows, dataframe.WithTypes(map[string]series.Type{"Date": series.String, "Unit": series.String}))
+	require.Equal(t, []string{"2022-07-13T08:30:00-04:00", "2022-07-13T00:00:00-04:00", "2022-07-13T14:00:00-04:00"}, df.Col("Date").Records())
+	require.Equal(t, []string{"CPI", "Treasury Budget", "FOMC Rate Decision"}, df.Col("Release").Records())
+
+	// Values that do not parse are missing, keeping their raw text
+	require.Equal(t, []string{"9.1%", "", "tentative"}, df.Col("Actual").Records())
+	require.Equal(t, "0.25% - 0.50%", df.Col("Expected").Elem(2).String())
+	require.True(t, math.IsNaN(df.Col("Actual Value").Elem(2).Float()))
+	require.True(t, math.IsNaN(df.Col("Expected Value").Elem(2).Float()))
+	require.Equal(t, 1.75, df.Col("Prior Value").Elem(2).Float())
+	require.Equal(t, []string{"percent", "billions", "percent"}, df.Col("Unit").Records())
+	require.True(t, math.IsNaN(df.Col("Surprise").Elem(2).Float()))
+}
+
+func TestParseValue(t *testing.T) {
+	values := []struct {
+		raw      string
+		number   float64
+		unit     Unit
+		currency string
+	}{
+		{"3.2%", 3.2, UnitPercent, ""},
+		{"215K", 215000, UnitThousands, ""},
+		{"+515K", 515000, UnitThousands, ""},
+		{"-4.55M", -4550000, UnitMillions, ""},
+		{"-1.1B", -1.1e9, UnitBillions, ""},
+		{"$137.4B", 137.4e9, UnitBillions, "$"},
+		{"-206 bcf", -206, Unit("bcf"), ""},
+		{"23.2", 23.2, UnitIndex, ""},
+	}
+	for _, v := range values {
+		value, err := ParseValue(v.raw)
+		require.Nil(t, err, v.raw)
+		require.InDelta(t, v.number, value.Number, 1e-3, v.raw)
+		require.Equal(t, v.unit, value.Unit, v.raw)
+		require.Equal(t, v.currency, value.Currency, v.raw)
+	}
+
+	for _, raw := range []string{"", "-", "NA"} {
+		value, err := ParseValue(raw)
+		require.Nil(t, err)
+		require.True(t, value.Missing())
+	}
+	_, err := ParseValue("tentative")
+	require.NotNil(t, err)
+
+	actual, _ := ParseValue("286K")
+	expected, _ := ParseValue("211K")
+	difference, percent := Surprise(actual, expected)
+	require.Equal(t, 75000.0, difference)
+	require.InDelta(t, 35.545, percent, 1e-3)
+
+	missing, _ := ParseValue("NA")
+	difference, percent = Surprise(actual, missing)
+	require.True(t, math.IsNaN(difference) && math.IsNaN(percent))
+
+	r, err := recorder.New("cassettes/calendar")
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	df, err := client.GetCalendar()
+	require.Nil(t, err)
+	claims := df.Filter(dataframe.F{Colname: "Release", Comparator: series.Eq, Comparando: "Initial Claims"})
+	require.Equal(t, 1, claims.Nrow())
+	require.Equal(t, "thousands", claims.Col("Unit").Records()[0])
+	require.Equal(t, 75000.0, claims.Col("Surprise").Float()[0])
+}
+
+func TestResolveYear(t *testing.T) {
+	values := []struct {
+		header    string
+		reference time.Time
+		expected  int
+	}{
+		{"Mon Jan 17", time.Date(2022, time.January, 22, 1, 56, 55, 0, time.UTC), 2022},
+		{"Mon Dec 30", time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC), 2024},
+		{"Thu Jan 2", time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC), 2025},
+		{"Thu Feb 29", time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC), 2024},
+	}
+	for _, v := range values {
+		year, err := ResolveYear(v.header, v.reference)
+		require.Nil(t, err, v.header)
+		require.Equal(t, v.expected, year, v.header)
+	}
+
+	for _, header := range []string{"Thu Jan 17", "Jan 17", "Mon Foo 17"} {
+		_, err := ResolveYear(header, time.Date(2022, time.January, 22, 0, 0, 0, 0, time.UTC))
+		require.NotNil(t, err, header)
+	}
+
+	doc, err := utils.GenerateDocument("<html><body><div class=\"copyright\">© 2022</div></body></html>")
+	require.Nil(t, err)
+	_, err = Scrape(doc, time.Now())
+	require.NotNil(t, err)
+}
+
+func TestWriteICS(t *testing.T) {
+	r, err := recorder.New("cassettes/calendar")
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	df, err := client.GetCalendar()
+	require.Nil(t, err)
+
+	var ics bytes.Buffer
+	require.Nil(t, WriteICS(&ics, df, time.Date(2022, time.January, 22, 1, 56, 55, 0, time.UTC)))
+	output := ics.String()
+	require.Equal(t, df.Nrow(), strings.Count(output, "BEGIN:VEVENT"))
+	require.Contains(t, output, "SUMMARY:Initial Claims\r\n")
+	require.Contains(t, output, "DTSTART;TZID=America/New_York:20220120T083000\r\n")
+	require.Contains(t, output, "DESCRIPTION:Impact: ")
+}
+
+func TestFilterCalendar(t *testing.T) {
+	r, err := recorder.New("cassettes/calendar")
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	calendar, err := client.GetCalendar()
+	require.Nil(t, err)
+
+	df, err := FilterCalendar(calendar, Filter{Groups: []string{"housing"}})
+	require.Nil(t, err)
+	require.Greater(t, df.Nrow(), 0)
+	for _, group := range df.Col("Group").Records() {
+		require.Equal(t, "housing", group)
+	}
+
+	df, err = FilterCalendar(calendar, Filter{MinImpact: "moderate", Match: regexp.MustCompile(`(?i)claims`)})
+	require.Nil(t, err)
+	for i, impact := range df.Col("Impact").Records() {
+		require.Contains(t, []string{"moderate", "critical"}, impact)
+		require.Contains(t, df.Col("Release").Records()[i], "Claims")
+	}
+
+	require.Equal(t, "employment", GroupRelease("Nonfarm Payrolls"))
+	require.Equal(t, "inflation", GroupRelease("Core CPI m/m"))
+	require.Equal(t, "", GroupRelease("Treasury Budget"))
+
+	_, err = FilterCalendar(calendar, Filter{MinImpact: "extreme"})
+	require.NotNil(t, err)
+	_, err = FilterCalendar(calendar, Filter{Groups: []string{"energy"}})
+	require.NotNil(t, err)
+}
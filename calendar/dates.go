@@ -0,0 +1,63 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// ResolveYear returns the year of a calendar week header, i.e. "Mon Jan 17", which omits it. Of the years around
+// the reference time, the closest one on which the date falls on the listed weekday is chosen, so the days of a
+// week spanning New Year resolve to different years.
+func ResolveYear(header string, reference time.Time) (int, error) {
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return 0, fmt.Errorf("error unexpected calendar header '%s', expected a weekday, month and day", header)
+	}
+	weekday, err := parseWeekday(fields[0])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing calendar header '%s': %v", header, err)
+	}
+	monthDay, err := time.Parse("Jan 2", fields[1]+" "+fields[2])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing calendar header '%s': %v", header, err)
+	}
+
+	reference = reference.In(utils.Eastern)
+	year, distance := 0, time.Duration(0)
+	for _, candidate := range []int{reference.Year() - 1, reference.Year(), reference.Year() + 1} {
+		date := time.Date(candidate, monthDay.Month(), monthDay.Day(), 12, 0, 0, 0, utils.Eastern)
+		// Feb 29 normalizes to Mar 1 outside of leap years
+		if date.Day() != monthDay.Day() || date.Weekday() != weekday {
+			continue
+		}
+		d := date.Sub(reference)
+		if d < 0 {
+			d = -d
+		}
+		if year == 0 || d < distance {
+			year, distance = candidate, d
+		}
+	}
+	if year == 0 {
+		return 0, fmt.Errorf("error calendar header '%s' is not a date near %s", header, reference.Format("2006-01-02"))
+	}
+	return year, nil
+}
+
+// parseWeekday parses an abbreviated weekday, i.e. "Mon"
+func parseWeekday(name string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String()[:3], name) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday '%s'", name)
+}
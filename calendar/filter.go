@@ -0,0 +1,150 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// Impacts lists the release impacts from least to most market moving
+var Impacts = []string{"low", "moderate", "critical"}
+
+// ReleaseGroups is a curated grouping of market moving releases, matched against release names
+var ReleaseGroups = map[string]*regexp.Regexp{
+	"employment": regexp.MustCompile(`(?i)nonfarm|payroll|unemployment|jobless|initial claims|continuing claims|jolts|hourly earnings|employment`),
+	"inflation":  regexp.MustCompile(`(?i)\bcpi\b|\bppi\b|\bpce\b|consumer price|producer price|import prices|export prices|inflation`),
+	"gdp":        regexp.MustCompile(`(?i)\bgdp\b|gross domestic`),
+	"housing":    regexp.MustCompile(`(?i)housing|home sales|building permits|case-shiller|house price|mortgage|construction spending`),
+	"sentiment":  regexp.MustCompile(`(?i)sentiment|confidence|empire state|philadelphia fed|\bism\b|\bpmi\b|leading economic|nahb`),
+}
+
+// Filter selects calendar rows. Zero-valued fields select every row.
+type Filter struct {
+	// MinImpact selects releases of at least this impact, i.e. "moderate" selects moderate and critical releases
+	MinImpact string
+	// Match selects releases whose name matches the regular expression
+	Match *regexp.Regexp
+	// Groups selects releases of any of the ReleaseGroups
+	Groups []string
+}
+
+// GetFilteredCalendar returns a DataFrame containing this week's economic calendar releases selected by the filter
+func (c *Client) GetFilteredCalendar(filter Filter) (*dataframe.DataFrame, error) {
+	df, err := c.GetCalendar()
+	if err != nil {
+		return nil, err
+	}
+	return FilterCalendar(df, filter)
+}
+
+// GroupRelease returns the group of a release name, or an empty string when it belongs to none
+func GroupRelease(release string) string {
+	for _, group := range GroupNames() {
+		if ReleaseGroups[group].MatchString(release) {
+			return group
+		}
+	}
+	return ""
+}
+
+// GroupNames returns the names of the ReleaseGroups, sorted
+func GroupNames() []string {
+	names := make([]string, 0, len(ReleaseGroups))
+	for name := range ReleaseGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FilterCalendar returns the rows of a calendar DataFrame selected by the filter
+func FilterCalendar(df *dataframe.DataFrame, filter Filter) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+
+	names := df.Names()
+	column := func(name string) ([]string, error) {
+		if !utils.Contains(names, name) {
+			return nil, fmt.Errorf("error calendar DataFrame has no '%s' column", name)
+		}
+		return df.Col(name).Records(), nil
+	}
+
+	var keep []func(i int) bool
+	if filter.MinImpact != "" {
+		minimum := impactRank(filter.MinImpact)
+		if minimum < 0 {
+			return nil, fmt.Errorf("error impact '%s' not found, expected one of: %s", filter.MinImpact, strings.Join(Impacts, ", "))
+		}
+		impacts, err := column("Impact")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) bool { return impactRank(impacts[i]) >= minimum })
+	}
+	if filter.Match != nil || len(filter.Groups) > 0 {
+		for _, group := range filter.Groups {
+			if _, exists := ReleaseGroups[strings.ToLower(group)]; !exists {
+				return nil, fmt.Errorf("error release group '%s' not found, expected one of: %s", group, strings.Join(GroupNames(), ", "))
+			}
+		}
+		releases, err := column("Release")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) bool {
+			if filter.Match != nil && !filter.Match.MatchString(releases[i]) {
+				return false
+			}
+			if len(filter.Groups) == 0 {
+				return true
+			}
+			for _, group := range filter.Groups {
+				if ReleaseGroups[strings.ToLower(group)].MatchString(releases[i]) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	var indexes []int
+	for i := 0; i < df.Nrow(); i++ {
+		selected := true
+		for _, k := range keep {
+			if !k(i) {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) == 0 {
+		return emptyDataFrame(names, df.Types())
+	}
+	result := df.Subset(indexes)
+	return &result, result.Error()
+}
+
+// impactRank returns the position of an impact in Impacts, or -1 for unrated releases
+func impactRank(impact string) int {
+	for i, name := range Impacts {
+		if strings.EqualFold(name, impact) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// ReleaseDuration is the length of calendar release events in iCalendar files
+const ReleaseDuration = 15 * time.Minute
+
+// WriteICS writes the releases of a calendar DataFrame as an iCalendar file. Each release's impact, period and
+// values are listed in its description.
+func WriteICS(w io.Writer, df *dataframe.DataFrame, stamp time.Time) error {
+	if df.Error() != nil {
+		return df.Error()
+	}
+	names := df.Names()
+	for _, name := range []string{"Date", "Release"} {
+		if !utils.Contains(names, name) {
+			return fmt.Errorf("error calendar DataFrame has no '%s' column", name)
+		}
+	}
+
+	var events []utils.ICSEvent
+	for _, row := range df.Maps() {
+		raw := fmt.Sprint(row["Date"])
+		date, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("error parsing calendar date '%s': %v", raw, err)
+		}
+		release := fmt.Sprint(row["Release"])
+
+		var description []string
+		for _, name := range []string{"Impact", "For", "Actual", "Expected", "Prior"} {
+			value, exists := row[name]
+			if !exists || value == nil {
+				continue
+			}
+			if text := fmt.Sprint(value); text != "" && text != "NaN" {
+				description = append(description, fmt.Sprintf("%s: %s", name, text))
+			}
+		}
+
+		events = append(events, utils.ICSEvent{
+			UID:         utils.ICSUID(date.Format("20060102T1504"), release),
+			Summary:     release,
+			Description: strings.Join(description, "\n"),
+			Start:       date,
+			End:         date.Add(ReleaseDuration),
+		})
+	}
+	return utils.WriteICS(w, "Finviz Economic Calendar", stamp, events)
+}
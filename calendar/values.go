@@ -0,0 +1,107 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit is the unit of an economic indicator value
+type Unit string
+
+const (
+	UnitIndex     Unit = "index"
+	UnitPercent   Unit = "percent"
+	UnitThousands Unit = "thousands"
+	UnitMillions  Unit = "millions"
+	UnitBillions  Unit = "billions"
+	UnitTrillions Unit = "trillions"
+)
+
+// unitSuffixes maps the suffixes of calendar values to their units and multiples
+var unitSuffixes = map[string]struct {
+	unit     Unit
+	multiple float64
+}{
+	"%": {UnitPercent, 1},
+	"K": {UnitThousands, 1e3},
+	"M": {UnitMillions, 1e6},
+	"B": {UnitBillions, 1e9},
+	"T": {UnitTrillions, 1e12},
+}
+
+// Value is a parsed economic indicator value, i.e. "286K", "0.8%" or "$137.4B"
+type Value struct {
+	// Number is the full value, i.e. 286000 for "286K", or the percentage for percent values, i.e. 0.8 for "0.8%"
+	Number float64
+	Unit   Unit
+	// Currency is the currency symbol of the value, if any
+	Currency string
+}
+
+// Missing reports whether the value was not listed
+func (v Value) Missing() bool {
+	return math.IsNaN(v.Number)
+}
+
+// ParseValue parses a calendar value. Missing values, i.e. "", "-" and "NA", have a NaN Number.
+// Values with other unit words, i.e. "-206 bcf", keep the word as their unit.
+func ParseValue(raw string) (Value, error) {
+	value := Value{Number: math.NaN()}
+	text := strings.TrimSpace(raw)
+	switch text {
+	case "", "-", "NA", "N/A", "NaN":
+		return value, nil
+	}
+
+	if fields := strings.Fields(text); len(fields) == 2 {
+		text, value.Unit = fields[0], Unit(fields[1])
+	} else if len(fields) > 2 {
+		return value, fmt.Errorf("error parsing calendar value '%s'", raw)
+	}
+
+	sign := ""
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		sign, text = text[:1], text[1:]
+	}
+	if strings.HasPrefix(text, "$") {
+		value.Currency, text = "$", text[1:]
+	}
+
+	multiple := 1.0
+	if len(text) > 0 {
+		if suffix, exists := unitSuffixes[text[len(text)-1:]]; exists {
+			text, multiple = text[:len(text)-1], suffix.multiple
+			value.Unit = suffix.unit
+		}
+	}
+	if value.Unit == "" {
+		value.Unit = UnitIndex
+	}
+
+	number, err := strconv.ParseFloat(sign+strings.ReplaceAll(text, ",", ""), 64)
+	if err != nil {
+		return Value{Number: math.NaN()}, fmt.Errorf("error parsing calendar value '%s': %v", raw, err)
+	}
+	value.Number = number * multiple
+	return value, nil
+}
+
+// Surprise returns the actual minus the expected value, and that difference as a percent of the expected value.
+// Both are NaN when either value is missing, and the percent is NaN when nothing was expected.
+func Surprise(actual, expected Value) (difference, percent float64) {
+	if actual.Missing() || expected.Missing() {
+		return math.NaN(), math.NaN()
+	}
+	difference = actual.Number - expected.Number
+	if expected.Number == 0 {
+		return difference, math.NaN()
+	}
+	return difference, difference / math.Abs(expected.Number) * 100
+}
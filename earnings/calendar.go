@@ -0,0 +1,308 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package earnings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/pkg/errors"
+
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/utils"
+)
+
+// ScreenerURL is the custom screener view listing the ticker, company, sector, industry, country, market cap,
+// EPS (ttm), price and earnings date columns
+const ScreenerURL = "https://finviz.com/screener.ashx?v=152&c=1,2,3,4,5,6,16,65,68&o=earningsdate"
+
+// Periods lists the earnings date screener filters, from narrowest to widest
+var Periods = []string{
+	"todaybefore", "todayafter", "today",
+	"tomorrowbefore", "tomorrowafter", "tomorrow",
+	"yesterdaybefore", "yesterdayafter", "yesterday",
+	"nextdays5", "prevdays5",
+	"thisweek", "nextweek", "prevweek",
+	"thismonth",
+}
+
+// Screener runs a Finviz screen, i.e. *screener.Client
+type Screener interface {
+	GetScreenerResults(url string) (*dataframe.DataFrame, error)
+}
+
+// Quoter returns quotes, i.e. *quote.Client, whose EPS next Q column is the consensus EPS estimate
+type Quoter interface {
+	GetQuotes(tickers []string) (quote.Results, error)
+}
+
+// CalendarHeaders are the columns of an earnings calendar DataFrame
+var CalendarHeaders = []string{"Date", "Timing", "Ticker", "Company", "Sector", "Industry", "Country", "Market Cap", "EPS (ttm)", "Price"}
+
+// GetCalendar screens the tickers reporting earnings in one of the Periods, resolving dates against the
+// reference time, usually now. Rows are sorted by date, with releases before the open first.
+func GetCalendar(s Screener, period string, reference time.Time) (*dataframe.DataFrame, error) {
+	if !utils.Contains(Periods, period) {
+		return nil, fmt.Errorf("error earnings period '%s' not found, expected one of: %s", period, strings.Join(Periods, ", "))
+	}
+
+	df, err := s.GetScreenerResults(fmt.Sprintf("%s&f=earningsdate_%s", ScreenerURL, period))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error screening earnings for period '%s'", period)
+	}
+	return CalendarFromDataFrame(df, reference)
+}
+
+// GetDateRange screens the periods overlapping the range and returns the earnings from one date to another,
+// inclusive. Finviz only lists earnings dates from the previous week to the end of the month or next week.
+func GetDateRange(s Screener, from, to, reference time.Time) (*dataframe.DataFrame, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("error earnings range ends (%s) before it starts (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	// Weeks are screened first, and the month only for days they leave uncovered
+	uncovered := make(map[string]bool)
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		uncovered[day.Format("2006-01-02")] = true
+	}
+
+	var rows [][]string
+	for _, period := range []string{"prevweek", "thisweek", "nextweek", "thismonth"} {
+		start, end := PeriodSpan(period, reference)
+		needed := false
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if uncovered[day.Format("2006-01-02")] {
+				needed = true
+				delete(uncovered, day.Format("2006-01-02"))
+			}
+		}
+		if !needed {
+			continue
+		}
+		df, err := GetCalendar(s, period, reference)
+		if err != nil {
+			return nil, err
+		}
+		if rows == nil {
+			rows = [][]string{CalendarHeaders}
+		}
+		for _, row := range df.Records()[1:] {
+			if !containsRow(rows, row) {
+				rows = append(rows, row)
+			}
+		}
+	}
+	if rows == nil {
+		start, _ := PeriodSpan("prevweek", reference)
+		_, end := PeriodSpan("nextweek", reference)
+		if monthEnd := endOfMonth(reference); monthEnd.After(end) {
+			end = monthEnd
+		}
+		return nil, fmt.Errorf("error earnings dates are only listed from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	var selected [][]string
+	for _, row := range rows[1:] {
+		date, err := time.ParseInLocation("2006-01-02", row[0], utils.Eastern)
+		if err != nil {
+			return nil, err
+		}
+		if !date.Before(truncateDay(from)) && !date.After(to) {
+			selected = append(selected, row)
+		}
+	}
+	sortRows(selected)
+	return loadCalendar(selected)
+}
+
+// PeriodSpan returns the first and last day listed by an earnings period, relative to the reference time
+func PeriodSpan(period string, reference time.Time) (start, end time.Time) {
+	today := truncateDay(reference)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	switch period {
+	case "today", "todaybefore", "todayafter":
+		return today, today
+	case "tomorrow", "tomorrowbefore", "tomorrowafter":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 1)
+	case "yesterday", "yesterdaybefore", "yesterdayafter":
+		return today.AddDate(0, 0, -1), today.AddDate(0, 0, -1)
+	case "nextdays5":
+		return today, today.AddDate(0, 0, 5)
+	case "prevdays5":
+		return today.AddDate(0, 0, -5), today
+	case "thisweek":
+		return monday, monday.AddDate(0, 0, 6)
+	case "nextweek":
+		return monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 13)
+	case "prevweek":
+		return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)
+	case "thismonth":
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, utils.Eastern), endOfMonth(today)
+	default:
+		return time.Time{}, time.Time{}
+	}
+}
+
+// CalendarFromDataFrame converts screener results with an Earnings column into an earnings calendar
+func CalendarFromDataFrame(df *dataframe.DataFrame, reference time.Time) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	names := df.Names()
+	for _, name := range []string{"Ticker", "Earnings"} {
+		if !utils.Contains(names, name) {
+			return nil, fmt.Errorf("error screener results have no '%s' column", name)
+		}
+	}
+	column := func(name string) []string {
+		if utils.Contains(names, name) {
+			return df.Col(name).Records()
+		}
+		values := make([]string, df.Nrow())
+		for i := range values {
+			values[i] = "NaN"
+		}
+		return values
+	}
+
+	earnings := column("Earnings")
+	columns := [][]string{
+		column("Ticker"), column("Company"), column("Sector"), column("Industry"),
+		column("Country"), column("Market Cap"), column("EPS"), column("Price"),
+	}
+
+	var rows [][]string
+	for i, raw := range earnings {
+		if raw == "" || raw == "-" || raw == "NaN" {
+			continue
+		}
+		date, timing, err := ParseEarningsDate(raw, reference)
+		if err != nil {
+			return nil, err
+		}
+		row := []string{date.Format("2006-01-02"), timing}
+		for _, values := range columns {
+			row = append(row, values[i])
+		}
+		rows = append(rows, row)
+	}
+	sortRows(rows)
+	return loadCalendar(rows)
+}
+
+// AddEstimates adds the consensus EPS estimate of the coming quarter, the quotes' EPS next Q, as the EPS Estimate column
+func AddEstimates(df *dataframe.DataFrame, q Quoter) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	tickers := df.Col("Ticker").Records()
+	estimates := make([]float64, len(tickers))
+	for i := range estimates {
+		estimates[i] = math.NaN()
+	}
+	if len(tickers) > 0 {
+		results, err := q.GetQuotes(tickers)
+		if err != nil {
+			return nil, err
+		}
+		quotes := results.Data
+		if quotes == nil {
+			return nil, fmt.Errorf("error no quotes returned for the earnings tickers")
+		}
+		lookup := make(map[string]float64, quotes.Nrow())
+		quoteTickers := quotes.Col("Ticker").Records()
+		for i, estimate := range quotes.Col("EPS next Q").Float() {
+			lookup[quoteTickers[i]] = estimate
+		}
+		// Tickers without a quote keep a NaN estimate
+		for i, ticker := range tickers {
+			if estimate, exists := lookup[ticker]; exists {
+				estimates[i] = estimate
+			}
+		}
+	}
+
+	result := df.Mutate(series.New(estimates, series.Float, "EPS Estimate"))
+	return &result, result.Error()
+}
+
+// Group lists the tickers reporting on a date at the same time of day
+type Group struct {
+	Date    string
+	Timing  string
+	Tickers []string
+}
+
+// GroupByDate groups an earnings calendar by date and timing, in calendar order
+func GroupByDate(df *dataframe.DataFrame) ([]Group, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	// Homepage earnings list the timing in the date, i.e. "Jan 12/a"
+	var groups []Group
+	timings := make([]string, df.Nrow())
+	if utils.Contains(df.Names(), "Timing") {
+		timings = df.Col("Timing").Records()
+	}
+	tickers := df.Col("Ticker").Records()
+	for i, date := range df.Col("Date").Records() {
+		if n := len(groups); n > 0 && groups[n-1].Date == date && groups[n-1].Timing == timings[i] {
+			groups[n-1].Tickers = append(groups[n-1].Tickers, tickers[i])
+			continue
+		}
+		groups = append(groups, Group{Date: date, Timing: timings[i], Tickers: []string{tickers[i]}})
+	}
+	return groups, nil
+}
+
+// sortRows sorts calendar rows by date, then releases before the open, during the day, and after the close
+func sortRows(rows [][]string) {
+	order := map[string]int{BeforeMarket: 0, "": 1, AfterMarket: 2}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i][0] != rows[j][0] {
+			return rows[i][0] < rows[j][0]
+		}
+		return order[rows[i][1]] < order[rows[j][1]]
+	})
+}
+
+func loadCalendar(rows [][]string) (*dataframe.DataFrame, error) {
+	if len(rows) == 0 {
+		columns := make([]series.Series, len(CalendarHeaders))
+		for i, name := range CalendarHeaders {
+			columns[i] = series.New([]string{}, series.String, name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+	df := dataframe.LoadRecords(append([][]string{CalendarHeaders}, rows...),
+		dataframe.WithTypes(map[string]series.Type{"Date": series.String, "Timing": series.String, "Ticker": series.String}))
+	return &df, df.Error()
+}
+
+func containsRow(rows [][]string, row []string) bool {
+	for _, r := range rows {
+		if r[0] == row[0] && r[2] == row[2] {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.In(utils.Eastern)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, utils.Eastern)
+}
+
+func endOfMonth(t time.Time) time.Time {
+	t = t.In(utils.Eastern)
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, utils.Eastern)
+}
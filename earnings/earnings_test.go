@@ -6,13 +6,18 @@
 package earnings
 
 import (
+	"bytes"
+	"math"
 	"net"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/corpix/uarand"
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
 	"github.com/stretchr/testify/require"
 
 	"github.com/d3an/finviz/utils"
@@ -56,3 +61,147 @@ func TestGetEarnings(t *testing.T) {
 		utils.PrintFullDataFrame(earnings)
 	}()
 }
+
+func TestWriteICS(t *testing.T) {
+	reference := time.Date(2022, time.January, 2, 12, 0, 0, 0, time.UTC)
+	date, timing, err := ParseEarningsDate("Dec 30/b", reference)
+	require.Nil(t, err)
+	require.Equal(t, "2021-12-30", date.Format("2006-01-02"))
+	require.Equal(t, BeforeMarket, timing)
+	_, _, err = ParseEarningsDate("Jan 12/x", reference)
+	require.NotNil(t, err)
+
+	r, err := recorder.New("cassettes/earnings")
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	df, err := client.GetEarnings()
+	require.Nil(t, err)
+
+	var ics bytes.Buffer
+	require.Nil(t, WriteICS(&ics, df, time.Date(2022, time.January, 14, 12, 0, 0, 0, time.UTC)))
+	output := ics.String()
+	require.Equal(t, df.Nrow(), strings.Count(output, "BEGIN:VEVENT"))
+	require.Contains(t, output, "DTSTART;VALUE=DATE:20220112\r\nDTEND;VALUE=DATE:20220113\r\nSUMMARY:KBH earnings (after market close)\r\n")
+	require.Contains(t, output, "SUMMARY:JPM earnings\r\n")
+}
+
+func TestGetCalendar(t *testing.T) {
+	headers := []string{"Ticker", "Company", "Sector", "Industry", "Country", "Market Cap", "EPS", "Price", "Earnings"}
+	s := &test.Screener{Results: map[string][][]string{
+		ScreenerURL + "&f=earningsdate_thisweek": {headers,
+			{"DAL", "Delta Air Lines, Inc.", "Industrials", "Airlines", "USA", "25.91B", "-1.70", "40.45", "Jan 13/b"},
+			{"JPM", "JPMorgan Chase & Co.", "Financial", "Banks - Diversified", "USA", "467.10B", "15.33", "158.24", "Jan 14/b"},
+			{"KBH", "KB Home", "Consumer Cyclical", "Residential Construction", "USA", "3.91B", "5.01", "43.20", "Jan 12/a"},
+			{"TSM", "Taiwan Semiconductor Manufacturing Company Limited", "Technology", "Semiconductors", "Taiwan", "654.59B", "3.70", "139.81", "Jan 13/a"},
+		},
+		ScreenerURL + "&f=earningsdate_nextweek": {headers,
+			{"NFLX", "Netflix, Inc.", "Communication Services", "Entertainment", "USA", "226.00B", "11.24", "510.80", "Jan 20/a"},
+			{"PG", "The Procter & Gamble Company", "Consumer Defensive", "Household & Personal Products", "USA", "385.70B", "5.69", "160.67", "Jan 19/b"},
+		},
+	}}
+	reference := time.Date(2022, time.January, 12, 12, 0, 0, 0, utils.Eastern)
+
+	df, err := GetCalendar(s, "thisweek", reference)
+	require.Nil(t, err)
+	require.Equal(t, []string{"KBH", "DAL", "TSM", "JPM"}, df.Col("Ticker").Records())
+	require.Equal(t, []string{"after", "before", "after", "before"}, df.Col("Timing").Records())
+	require.Equal(t, "2022-01-13", df.Col("Date").Records()[1])
+	marketCap, err := df.Col("Market Cap").Elem(1).Int()
+	require.Nil(t, err)
+	require.Equal(t, 25910000000, marketCap)
+
+	groups, err := GroupByDate(df)
+	require.Nil(t, err)
+	require.Equal(t, []Group{
+		{Date: "2022-01-12", Timing: "after", Tickers: []string{"KBH"}},
+		{Date: "2022-01-13", Timing: "before", Tickers: []string{"DAL"}},
+		{Date: "2022-01-13", Timing: "after", Tickers: []string{"TSM"}},
+		{Date: "2022-01-14", Timing: "before", Tickers: []string{"JPM"}},
+	}, groups)
+
+	df, err = GetDateRange(s, time.Date(2022, time.January, 14, 0, 0, 0, 0, utils.Eastern), time.Date(2022, time.January, 19, 0, 0, 0, 0, utils.Eastern), reference)
+	require.Nil(t, err)
+	require.Equal(t, []string{"JPM", "PG"}, df.Col("Ticker").Records())
+
+	_, err = GetDateRange(s, time.Date(2022, time.March, 1, 0, 0, 0, 0, utils.Eastern), time.Date(2022, time.March, 5, 0, 0, 0, 0, utils.Eastern), reference)
+	require.NotNil(t, err)
+	_, err = GetCalendar(s, "nextyear", reference)
+	require.NotNil(t, err)
+}
+
+func TestHistory(t *testing.T) {
+	headers := []string{"Ticker", "Earnings", "EPS next Q", "EPS (ttm)", "EPS Q/Q", "Sales Q/Q", "Prev Close", "Price"}
+	quotes := func(rows ...[]string) *dataframe.DataFrame {
+		df := dataframe.LoadRecords(append([][]string{headers}, rows...))
+		return utils.CleanFinvizDataFrame(&df)
+	}
+
+	// Before the release, at noon on Wednesday
+	h := &History{}
+	require.Nil(t, h.Record(quotes(
+		[]string{"NVDA", "Feb 16 AMC", "1.22", "3.00", "103.00%", "50.30%", "245.07", "265.11"},
+	), time.Date(2022, time.February, 16, 12, 0, 0, 0, utils.Eastern)))
+	require.Empty(t, h.Reports)
+	ttm := 3.0
+	require.Equal(t, Estimate{Date: "2022-02-16", Timing: AfterMarket, EPS: 1.22, TTM: &ttm}, h.Pending["NVDA"])
+
+	// During the session after the release, before the EPS (ttm) is updated
+	require.Nil(t, h.Record(quotes(
+		[]string{"NVDA", "Feb 16 AMC", "1.29", "3.00", "100.00%", "52.80%", "265.11", "250.00"},
+	), time.Date(2022, time.February, 17, 10, 0, 0, 0, utils.Eastern)))
+	require.Len(t, h.Reports, 1)
+	require.Empty(t, h.Pending)
+	require.Nil(t, h.Reports[0].EPS)
+	require.Nil(t, h.Reports[0].Reaction)
+
+	// After the close of the session after the release, a quarter's EPS of 1.30 doubling the year earlier's 0.65
+	require.Nil(t, h.Record(quotes(
+		[]string{"NVDA", "Feb 16 AMC", "1.29", "3.65", "100.00%", "52.80%", "265.11", "245.07"},
+	), time.Date(2022, time.February, 17, 17, 0, 0, 0, utils.Eastern)))
+	require.Len(t, h.Reports, 1)
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.Nil(t, h.Save(path))
+	h, err := LoadHistory(path)
+	require.Nil(t, err)
+
+	df, err := h.DataFrame([]string{"NVDA"})
+	require.Nil(t, err)
+	require.Equal(t, HistoryHeaders, df.Names())
+	require.Equal(t, []string{"2022-02-16"}, df.Col("Date").Records())
+	require.Equal(t, []string{AfterMarket}, df.Col("Timing").Records())
+	require.Equal(t, 1.22, df.Col("EPS Estimate").Elem(0).Float())
+	require.Equal(t, 1.3, df.Col("EPS").Elem(0).Float())
+	require.InDelta(t, 0.08, df.Col("EPS Surprise").Elem(0).Float(), 1e-9)
+	require.InDelta(t, 0.08/1.22, df.Col("EPS Surprise %").Elem(0).Float(), 1e-9)
+	require.InDelta(t, 1.0, df.Col("EPS Q/Q").Elem(0).Float(), 1e-9)
+	require.Equal(t, 265.11, df.Col("Prev Close").Elem(0).Float())
+	require.Equal(t, 245.07, df.Col("Close").Elem(0).Float())
+	require.InDelta(t, 245.07/265.11-1, df.Col("Reaction").Elem(0).Float(), 1e-9)
+
+	// A first fetch after the release has no estimate, so no surprise
+	h = &History{}
+	require.Nil(t, h.Record(quotes(
+		[]string{"NVDA", "Feb 16 AMC", "1.29", "3.65", "100.00%", "52.80%", "245.07", "240.00"},
+	), time.Date(2022, time.March, 1, 17, 0, 0, 0, utils.Eastern)))
+	df, err = h.DataFrame(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, df.Nrow())
+	require.True(t, math.IsNaN(df.Col("EPS Surprise").Elem(0).Float()))
+	require.True(t, math.IsNaN(df.Col("Reaction").Elem(0).Float()))
+
+	df, err = h.DataFrame([]string{"AMD"})
+	require.Nil(t, err)
+	require.Equal(t, 0, df.Nrow())
+
+	require.Equal(t, "2022-02-14", ReactionDay(time.Date(2022, time.February, 11, 0, 0, 0, 0, utils.Eastern), AfterMarket).Format("2006-01-02"))
+	date, timing, err := ParseEarningsDate("Oct 28 AMC", time.Date(2021, time.December, 27, 0, 0, 0, 0, utils.Eastern))
+	require.Nil(t, err)
+	require.Equal(t, "2021-10-28", date.Format("2006-01-02"))
+	require.Equal(t, AfterMarket, timing)
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package earnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// HistoryHeaders are the columns of an earnings history DataFrame. EPS Surprise % and Reaction are fractions.
+var HistoryHeaders = []string{
+	"Ticker", "Date", "Timing", "EPS Estimate", "EPS", "EPS Surprise", "EPS Surprise %", "EPS Q/Q", "Sales Q/Q",
+	"Prev Close", "Close", "Reaction",
+}
+
+// Report is a past earnings release of a ticker. Fields are nil until observed on a quote page.
+type Report struct {
+	Ticker string `json:"ticker"`
+	Date   string `json:"date"`
+	Timing string `json:"timing,omitempty"`
+	// EPSEstimate is the EPS next Q of the ticker's last quote before the release
+	EPSEstimate *float64 `json:"eps_estimate,omitempty"`
+	// EPS is the reported quarterly EPS. Quote pages do not list it, so it is derived from the change in EPS (ttm)
+	// across the release and the quarter's EPS Q/Q, and needs a quote from before the release.
+	EPS *float64 `json:"eps,omitempty"`
+	// PriorTTM is the EPS (ttm) of the ticker's last quote before the release
+	PriorTTM *float64 `json:"prior_eps_ttm,omitempty"`
+	// EPSGrowth and SalesGrowth are the EPS Q/Q and Sales Q/Q of the quarter, as fractions
+	EPSGrowth   *float64 `json:"eps_growth,omitempty"`
+	SalesGrowth *float64 `json:"sales_growth,omitempty"`
+	// PrevClose and Close are the closes before and on the first session after the release, and Reaction is the
+	// change between them as a fraction
+	PrevClose *float64 `json:"prev_close,omitempty"`
+	Close     *float64 `json:"close,omitempty"`
+	Reaction  *float64 `json:"reaction,omitempty"`
+}
+
+// Surprise returns the reported EPS minus the estimate, and that difference as a fraction of the estimate, or nil
+// when either is unknown
+func (r *Report) Surprise() (difference, fraction *float64) {
+	if r.EPS == nil || r.EPSEstimate == nil {
+		return nil, nil
+	}
+	d := *r.EPS - *r.EPSEstimate
+	if *r.EPSEstimate == 0 {
+		return &d, nil
+	}
+	f := d / math.Abs(*r.EPSEstimate)
+	return &d, &f
+}
+
+// Estimate is the consensus EPS estimate of an upcoming earnings release, with the EPS (ttm) before it
+type Estimate struct {
+	Date   string   `json:"date"`
+	Timing string   `json:"timing,omitempty"`
+	EPS    float64  `json:"eps"`
+	TTM    *float64 `json:"eps_ttm,omitempty"`
+}
+
+// History accumulates the earnings releases of tickers across quote fetches. It is not backfilled: quote pages
+// only list the next or last release, so the history of a ticker starts with its first fetch and grows each time
+// it is refreshed. Surprises need a fetch before the release, and reactions a fetch after the close of the first
+// session after it and before the next session opens, or else they are missing. Sales surprises are not available, since quote pages have no sales estimate.
+type History struct {
+	UpdatedAt time.Time           `json:"updated_at"`
+	Reports   []Report            `json:"reports"`
+	Pending   map[string]Estimate `json:"pending"`
+}
+
+// DefaultHistoryPath returns the file the CLI keeps the earnings history in
+func DefaultHistoryPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "finviz", "earnings_history.json"), nil
+}
+
+// LoadHistory reads a history file. A missing file is an empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{Pending: make(map[string]Estimate)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("error parsing earnings history file '%s': %v", path, err)
+	}
+	if h.Pending == nil {
+		h.Pending = make(map[string]Estimate)
+	}
+	return h, nil
+}
+
+// Save writes the history to a file
+func (h *History) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Record updates the history with quotes fetched at the provided time. Upcoming releases keep their EPS estimate
+// and EPS (ttm) until they are reported. Reported releases take the quarter's EPS and sales growth, the reported EPS
+// when it can be derived, and, when fetched after the close of the first session after the release, its closes.
+func (h *History) Record(quotes *dataframe.DataFrame, fetched time.Time) error {
+	if quotes.Error() != nil {
+		return quotes.Error()
+	}
+	for _, name := range []string{"Ticker", "Earnings"} {
+		if !utils.Contains(quotes.Names(), name) {
+			return fmt.Errorf("error quotes have no '%s' column", name)
+		}
+	}
+	if h.Pending == nil {
+		h.Pending = make(map[string]Estimate)
+	}
+
+	today := truncateDay(fetched)
+	tickers := quotes.Col("Ticker").Records()
+	dates := quotes.Col("Earnings").Records()
+	for i, ticker := range tickers {
+		if dates[i] == "" || dates[i] == "-" || dates[i] == "NaN" {
+			continue
+		}
+		date, timing, err := ParseEarningsDate(dates[i], fetched)
+		if err != nil {
+			return err
+		}
+		day := date.Format("2006-01-02")
+		value := func(name string) *float64 {
+			if !utils.Contains(quotes.Names(), name) {
+				return nil
+			}
+			if v := quotes.Col(name).Elem(i).Float(); !math.IsNaN(v) {
+				return &v
+			}
+			return nil
+		}
+
+		// Releases after the close of the fetch day have not been reported yet
+		if date.After(today) || date.Equal(today) && timing == AfterMarket && fetched.In(utils.Eastern).Hour() < 16 {
+			if estimate := value("EPS next Q"); estimate != nil {
+				h.Pending[ticker] = Estimate{Date: day, Timing: timing, EPS: *estimate, TTM: value("EPS (ttm)")}
+			}
+			continue
+		}
+
+		report := h.report(ticker, day)
+		if timing != "" {
+			report.Timing = timing
+		}
+		if estimate, exists := h.Pending[ticker]; exists && estimate.Date == day {
+			eps := estimate.EPS
+			report.EPSEstimate = &eps
+			report.PriorTTM = estimate.TTM
+			delete(h.Pending, ticker)
+		}
+		if growth := value("EPS Q/Q"); growth != nil {
+			report.EPSGrowth = growth
+		}
+		if growth := value("Sales Q/Q"); growth != nil {
+			report.SalesGrowth = growth
+		}
+		if eps := reportedEPS(report.PriorTTM, value("EPS (ttm)"), report.EPSGrowth); eps != nil {
+			report.EPS = eps
+		}
+
+		// Quotes only hold the reaction session's closes after it ends, and until the next session opens
+		if ReactionDay(date, report.Timing).Equal(today) && fetched.In(utils.Eastern).Hour() >= 16 {
+			report.PrevClose, report.Close = value("Prev Close"), value("Price")
+			if report.PrevClose != nil && report.Close != nil && *report.PrevClose != 0 {
+				reaction := *report.Close / *report.PrevClose - 1
+				report.Reaction = &reaction
+			}
+		}
+	}
+
+	sort.SliceStable(h.Reports, func(i, j int) bool {
+		if h.Reports[i].Ticker != h.Reports[j].Ticker {
+			return h.Reports[i].Ticker < h.Reports[j].Ticker
+		}
+		return h.Reports[i].Date < h.Reports[j].Date
+	})
+	h.UpdatedAt = fetched
+	return nil
+}
+
+// reportedEPS derives a quarter's EPS from the EPS (ttm) before and after its release, whose change is the EPS
+// minus the EPS of the quarter a year earlier, and the EPS Q/Q growth between those quarters. It is nil until the
+// EPS (ttm) is updated, and when the growth is unknown or zero.
+func reportedEPS(before, after, growth *float64) *float64 {
+	if before == nil || after == nil || growth == nil || *growth == 0 || *after == *before {
+		return nil
+	}
+	eps := (*after - *before) * (1 + *growth) / *growth
+	eps = math.Round(eps*1e4) / 1e4
+	return &eps
+}
+
+// report returns the report of a ticker on a date, adding it when missing
+func (h *History) report(ticker, date string) *Report {
+	for i := range h.Reports {
+		if h.Reports[i].Ticker == ticker && h.Reports[i].Date == date {
+			return &h.Reports[i]
+		}
+	}
+	h.Reports = append(h.Reports, Report{Ticker: ticker, Date: date})
+	return &h.Reports[len(h.Reports)-1]
+}
+
+// ReactionDay returns the first session trading on an earnings release: the release day for releases before the
+// open or during the day, and the next weekday for releases after the close
+func ReactionDay(date time.Time, timing string) time.Time {
+	day := truncateDay(date)
+	if timing != AfterMarket {
+		return day
+	}
+	day = day.AddDate(0, 0, 1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// DataFrame returns the reports of the tickers, or of every ticker when none are provided, oldest first
+func (h *History) DataFrame(tickers []string) (*dataframe.DataFrame, error) {
+	format := func(v *float64) string {
+		if v == nil {
+			return "NaN"
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+
+	rows := [][]string{HistoryHeaders}
+	for _, r := range h.Reports {
+		if len(tickers) > 0 && !utils.Contains(tickers, r.Ticker) {
+			continue
+		}
+		surprise, surprisePercent := r.Surprise()
+		rows = append(rows, []string{
+			r.Ticker, r.Date, r.Timing, format(r.EPSEstimate), format(r.EPS), format(surprise), format(surprisePercent),
+			format(r.EPSGrowth), format(r.SalesGrowth), format(r.PrevClose), format(r.Close), format(r.Reaction),
+		})
+	}
+
+	if len(rows) == 1 {
+		columns := make([]series.Series, len(HistoryHeaders))
+		for i, name := range HistoryHeaders {
+			columns[i] = series.New([]string{}, series.String, name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+	types := map[string]series.Type{"Ticker": series.String, "Date": series.String, "Timing": series.String}
+	for _, name := range HistoryHeaders[3:] {
+		types[name] = series.Float
+	}
+	df := dataframe.LoadRecords(rows, dataframe.WithTypes(types))
+	return &df, df.Error()
+}
+
+// GetHistory fetches the quotes of the tickers, records them in the history file at the path, and returns the
+// tickers' earnings history accumulated in it, which starts with the first fetch of each ticker
+func GetHistory(q Quoter, tickers []string, path string) (*dataframe.DataFrame, error) {
+	h, err := LoadHistory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := q.GetQuotes(tickers)
+	if err != nil {
+		return nil, err
+	}
+	if results.Data == nil {
+		return nil, fmt.Errorf("error no quotes returned for tickers: %v", tickers)
+	}
+	if err = h.Record(results.Data, time.Now()); err != nil {
+		return nil, err
+	}
+	if err = h.Save(path); err != nil {
+		return nil, err
+	}
+	return h.DataFrame(results.Data.Col("Ticker").Records())
+}
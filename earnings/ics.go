@@ -0,0 +1,122 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package earnings
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// Release timings relative to the trading session
+const (
+	BeforeMarket = "before"
+	AfterMarket  = "after"
+)
+
+// ParseEarningsDate parses an earnings date such as "Jan 12/a" from the homepage and screener, or "Oct 28 AMC" from
+// quote pages, where /b and BMO mark releases before the market opens, and /a and AMC after it closes. The year
+// is the one placing the date closest to the reference time.
+func ParseEarningsDate(raw string, reference time.Time) (date time.Time, timing string, err error) {
+	text := strings.TrimSpace(raw)
+	suffix := ""
+	if i := strings.Index(text, "/"); i >= 0 {
+		text, suffix = strings.TrimSpace(text[:i]), text[i+1:]
+	} else if fields := strings.Fields(text); len(fields) == 3 {
+		text, suffix = fields[0]+" "+fields[1], fields[2]
+	}
+	switch strings.ToLower(suffix) {
+	case "":
+	case "b", "bmo":
+		timing = BeforeMarket
+	case "a", "amc":
+		timing = AfterMarket
+	default:
+		return time.Time{}, "", fmt.Errorf("error unknown earnings timing in '%s'", raw)
+	}
+
+	monthDay, err := time.Parse("Jan 2", text)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("error parsing earnings date '%s': %v", raw, err)
+	}
+
+	reference = reference.In(utils.Eastern)
+	for _, year := range []int{reference.Year() - 1, reference.Year(), reference.Year() + 1} {
+		candidate := time.Date(year, monthDay.Month(), monthDay.Day(), 0, 0, 0, 0, utils.Eastern)
+		if date.IsZero() || absDuration(candidate.Sub(reference)) < absDuration(date.Sub(reference)) {
+			date = candidate
+		}
+	}
+	return date, timing, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// WriteICS writes the releases of an earnings DataFrame as an iCalendar file of all-day events. The year of
+// homepage dates is resolved against the reference time, usually the time the earnings were fetched.
+func WriteICS(w io.Writer, df *dataframe.DataFrame, reference time.Time) error {
+	if df.Error() != nil {
+		return df.Error()
+	}
+	names := df.Names()
+	for _, name := range []string{"Date", "Ticker"} {
+		if !utils.Contains(names, name) {
+			return fmt.Errorf("error earnings DataFrame has no '%s' column", name)
+		}
+	}
+
+	// Calendars from GetCalendar list full dates and a Timing column, and the homepage lists dates such as "Jan 12/a"
+	tickers := df.Col("Ticker").Records()
+	timings := make([]string, df.Nrow())
+	hasTiming := utils.Contains(names, "Timing")
+	if hasTiming {
+		timings = df.Col("Timing").Records()
+	}
+
+	var events []utils.ICSEvent
+	for i, raw := range df.Col("Date").Records() {
+		var date time.Time
+		var timing string
+		var err error
+		if hasTiming {
+			date, err = time.ParseInLocation("2006-01-02", raw, utils.Eastern)
+			timing = timings[i]
+		} else {
+			date, timing, err = ParseEarningsDate(raw, reference)
+		}
+		if err != nil {
+			return err
+		}
+
+		summary := fmt.Sprintf("%s earnings", tickers[i])
+		switch timing {
+		case BeforeMarket:
+			summary += " (before market open)"
+		case AfterMarket:
+			summary += " (after market close)"
+		}
+
+		events = append(events, utils.ICSEvent{
+			UID:         utils.ICSUID(date.Format("20060102"), tickers[i], "earnings"),
+			Summary:     summary,
+			Description: fmt.Sprintf("https://finviz.com/quote.ashx?t=%s", tickers[i]),
+			Start:       date,
+			End:         date.AddDate(0, 0, 1),
+			AllDay:      true,
+		})
+	}
+	return utils.WriteICS(w, "Finviz Earnings", reference, events)
+}
@@ -6,6 +6,13 @@
 package calendar
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
 	"github.com/spf13/cobra"
 
 	"github.com/d3an/finviz/calendar"
@@ -15,6 +22,10 @@ import (
 var (
 	outFile string
 
+	impact *utils.Enum
+	match  string
+	groups []string
+
 	// Cmd is the CLI subcommand for Finviz news
 	Cmd = &cobra.Command{
 		Use:     "calendar",
@@ -22,20 +33,62 @@ var (
 		Short:   "Finviz Economic Calendar",
 		Long:    "Finviz Economic Calendar returns this week's Economic Calendar.",
 		Run: func(cmd *cobra.Command, args []string) {
-			client := calendar.New(nil)
-			df, err := client.GetCalendar()
+			df, err := calendar.New(nil).GetCalendar()
 			if err != nil {
 				utils.Err(err)
 			}
 
-			if err = utils.ExportData(df, outFile); err != nil {
+			filter, err := newFilter()
+			if err != nil {
+				utils.Err(err)
+			}
+			if df, err = calendar.FilterCalendar(df, filter); err != nil {
+				utils.Err(err)
+			}
+
+			if err = export(df); err != nil {
 				utils.Err(err)
 			}
 		},
 	}
 )
 
+func newFilter() (filter calendar.Filter, err error) {
+	if impact.Value != "all" {
+		filter.MinImpact = impact.Value
+	}
+	if match != "" {
+		if filter.Match, err = regexp.Compile("(?i)" + match); err != nil {
+			return filter, err
+		}
+	}
+	filter.Groups = groups
+	return filter, nil
+}
+
+// export writes an iCalendar file for .ics files, and otherwise exports the data
+func export(df *dataframe.DataFrame) error {
+	if filepath.Ext(outFile) != ".ics" {
+		return utils.ExportData(df, outFile)
+	}
+
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	if err = calendar.WriteICS(f, df, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func init() {
-	// -o <filename>
-	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	// --impact moderate -m "claims" -g employment,inflation
+	// -o <filename>, where .ics writes an iCalendar file
+	impact = utils.NewEnum(append([]string{"all"}, calendar.Impacts...), "all")
+	Cmd.Flags().Var(impact, "impact", "minimum impact, all|low|moderate|critical")
+	Cmd.Flags().StringVarP(&match, "match", "m", "", "case-insensitive regular expression matched against releases")
+	Cmd.Flags().StringSliceVarP(&groups, "group", "g", nil, strings.Join(calendar.GroupNames(), ","))
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json|ics)")
 }
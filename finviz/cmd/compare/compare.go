@@ -0,0 +1,46 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package compare
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+
+	// Cmd is the CLI subcommand for Finviz peer comparisons
+	Cmd = &cobra.Command{
+		Use:     "compare <ticker>",
+		Aliases: []string{"cmp", "peers"},
+		Short:   "Finviz Peer Comparison",
+		Long: "Finviz Peer Comparison ranks a ticker against its Finviz peers on valuation, growth " +
+			"and profitability. The ticker is listed first and marked in the Subject column.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				utils.Err("ticker not provided")
+			}
+
+			client := quote.New(nil)
+			df, err := client.GetPeerComparison(args[0])
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+func init() {
+	// -o <filename>
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+}
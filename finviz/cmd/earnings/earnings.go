@@ -6,36 +6,164 @@
 package earnings
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
 	"github.com/spf13/cobra"
 
 	"github.com/d3an/finviz/earnings"
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/screener"
 	"github.com/d3an/finviz/utils"
 )
 
 var (
-	outFile string
+	outFile   string
+	period    string
+	from      string
+	to        string
+	estimates bool
+	group     bool
+	tickers   []string
+	history   string
 
 	// Cmd is the CLI subcommand for Finviz news
 	Cmd = &cobra.Command{
 		Use:     "earnings",
 		Aliases: []string{"e"},
 		Short:   "Finviz Earnings",
-		Long:    "Finviz Earnings returns the tickers with earnings releases left this week.",
+		Long: "Finviz Earnings returns the tickers with earnings releases left this week, or the full earnings " +
+			"calendar of a period or date range.",
 		Run: func(cmd *cobra.Command, args []string) {
-			client := earnings.New(nil)
-			df, err := client.GetEarnings()
+			var df *dataframe.DataFrame
+			var err error
+			switch {
+			case from != "" || to != "":
+				var start, end time.Time
+				if start, end, err = dateRange(); err != nil {
+					utils.Err(err)
+				}
+				df, err = earnings.GetDateRange(screener.New(nil), start, end, time.Now())
+			case period != "":
+				df, err = earnings.GetCalendar(screener.New(nil), period, time.Now())
+			default:
+				df, err = earnings.New(nil).GetEarnings()
+			}
 			if err != nil {
 				utils.Err(err)
 			}
 
-			if err = utils.ExportData(df, outFile); err != nil {
+			if estimates {
+				if df, err = earnings.AddEstimates(df, quote.New(nil)); err != nil {
+					utils.Err(err)
+				}
+			}
+
+			if group {
+				groups, err := earnings.GroupByDate(df)
+				if err != nil {
+					utils.Err(err)
+				}
+				for _, g := range groups {
+					timing := g.Timing
+					if timing == "" {
+						timing = "unspecified"
+					}
+					fmt.Printf("%s %-11s %s\n", g.Date, timing, strings.Join(g.Tickers, ", "))
+				}
+				return
+			}
+
+			if err = export(df); err != nil {
 				utils.Err(err)
 			}
 		},
 	}
 )
 
+// HistoryCmd is the CLI subcommand for the earnings history of tickers
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Finviz Earnings History",
+	Long: "Finviz Earnings History records the earnings releases of tickers from their quote pages and returns " +
+		"the releases recorded so far, with the EPS estimate, EPS surprise, growth and post-earnings price reaction " +
+		"of each. The history is accumulated locally, not backfilled: quote pages only list the next or last " +
+		"release, so a ticker's history starts with its first run. Surprises need a run before the release, and " +
+		"reactions a run after the close of the session after it, before the next open.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(tickers) == 0 {
+			utils.Err("error no tickers provided, i.e. -t NVDA")
+		}
+		path := history
+		if path == "" {
+			var err error
+			if path, err = earnings.DefaultHistoryPath(); err != nil {
+				utils.Err(err)
+			}
+		}
+
+		df, err := earnings.GetHistory(quote.New(nil), tickers, path)
+		if err != nil {
+			utils.Err(err)
+		}
+		if err = utils.ExportData(df, outFile); err != nil {
+			utils.Err(err)
+		}
+	},
+}
+
+// export writes an iCalendar file for .ics files, and otherwise exports the data
+func export(df *dataframe.DataFrame) error {
+	if filepath.Ext(outFile) != ".ics" {
+		return utils.ExportData(df, outFile)
+	}
+
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	if err = earnings.WriteICS(f, df, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// dateRange parses the --from and --to dates, each defaulting to the other
+func dateRange() (start, end time.Time, err error) {
+	if from == "" {
+		from = to
+	} else if to == "" {
+		to = from
+	}
+	if start, err = time.ParseInLocation("2006-01-02", from, utils.Eastern); err != nil {
+		return start, end, err
+	}
+	if end, err = time.ParseInLocation("2006-01-02", to, utils.Eastern); err != nil {
+		return start, end, err
+	}
+	return start, end, nil
+}
+
 func init() {
-	// -o <filename>
-	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	// --period thisweek|nextweek|...
+	// --from 2022-01-12 --to 2022-01-19
+	// --estimates --group
+	// -o <filename>, where .ics writes an iCalendar file
+	Cmd.Flags().StringVarP(&period, "period", "p", "", strings.Join(earnings.Periods, "|"))
+	Cmd.Flags().StringVar(&from, "from", "", "first date of the range, i.e. 2022-01-12")
+	Cmd.Flags().StringVar(&to, "to", "", "last date of the range, i.e. 2022-01-19")
+	Cmd.Flags().BoolVar(&estimates, "estimates", false, "add the EPS estimate of each ticker from its quote page")
+	Cmd.Flags().BoolVar(&group, "group", false, "print the tickers grouped by date and timing")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json|ics)")
+
+	// history -t NVDA,AMD --history <filename> -o <filename>
+	HistoryCmd.Flags().StringSliceVarP(&tickers, "tickers", "t", nil, "tickers, i.e. NVDA,AMD")
+	HistoryCmd.Flags().StringVar(&history, "history", "", "earnings history file, defaults to the user cache directory")
+	HistoryCmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	Cmd.AddCommand(HistoryCmd)
 }
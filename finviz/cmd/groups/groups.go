@@ -0,0 +1,63 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package groups
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/groups"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+	by      *utils.Enum
+	view    *utils.Enum
+	sector  string
+	order   string
+	columns []string
+
+	// Cmd is the CLI subcommand for Finviz groups
+	Cmd = &cobra.Command{
+		Use:     "groups",
+		Aliases: []string{"g"},
+		Short:   "Finviz Groups",
+		Long: "Finviz Groups returns the performance, valuation and other statistics of stocks grouped by sector, " +
+			"industry, country or market capitalization.",
+		Run: func(cmd *cobra.Command, args []string) {
+			df, err := groups.New(nil).GetGroups(groups.Query{
+				By:      by.Value,
+				Sector:  sector,
+				View:    view.Value,
+				Order:   order,
+				Columns: columns,
+			})
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+func init() {
+	// --by industry --sector technology --view performance --order -change
+	// --view custom -c "Name,Perf Week,Change"
+	// -o <filename>
+	by = utils.NewEnum(groups.Groupings, "sector")
+	view = utils.NewEnum(groups.ViewNames(), "overview")
+	Cmd.Flags().VarP(by, "by", "b", strings.Join(groups.Groupings, "|"))
+	Cmd.Flags().StringVarP(&sector, "sector", "s", "", "limit industry or capitalization groups to a sector, i.e. technology")
+	Cmd.Flags().VarP(view, "view", "v", strings.Join(groups.ViewNames(), "|"))
+	Cmd.Flags().StringVar(&order, "order", "name", "order by a column, descending with a leading '-', i.e. -change")
+	Cmd.Flags().StringSliceVarP(&columns, "columns", "c", nil, "custom view columns, i.e. \"Name,Perf Week,Change\"")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+}
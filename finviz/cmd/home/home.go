@@ -0,0 +1,127 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package home
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/home"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile  string
+	section  *utils.Enum
+	signal   string
+	appendTo bool
+	series   string
+	history  bool
+
+	// Cmd is the CLI subcommand for the Finviz homepage
+	Cmd = &cobra.Command{
+		Use:   "home",
+		Short: "Finviz Homepage",
+		Long: "Finviz Homepage returns a market overview of the homepage tables: signals such as top gainers and " +
+			"losers, chart patterns, headlines, major news, earnings, futures, forex and insider trading. " +
+			"The breadth subcommand returns the market breadth bars.",
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := home.New(nil).GetHome()
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if signal != "" {
+				df, err := results.Signal(signal)
+				if err != nil {
+					utils.Err(err)
+				}
+				if err = utils.ExportData(df, outFile); err != nil {
+					utils.Err(err)
+				}
+				return
+			}
+
+			if section.Value == "all" {
+				if outFile != "" {
+					utils.Err("error exporting the homepage requires a --section")
+				}
+				if results.Breadth != nil {
+					fmt.Println("BREADTH")
+					utils.PrintFullDataFrame(results.Breadth.DataFrame())
+				}
+				for _, name := range home.Sections {
+					df, _ := results.Section(name)
+					fmt.Printf("\n%s\n", strings.ToUpper(strings.ReplaceAll(name, "-", " ")))
+					utils.PrintFullDataFrame(df)
+				}
+				return
+			}
+
+			df, err := results.Section(section.Value)
+			if err != nil {
+				utils.Err(err)
+			}
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+// BreadthCmd is the CLI subcommand for the homepage market breadth
+var BreadthCmd = &cobra.Command{
+	Use:   "breadth",
+	Short: "Finviz Market Breadth",
+	Long: "Finviz Market Breadth returns the advancing vs declining, new high vs low and above vs below SMA50 and " +
+		"SMA200 issues of the homepage, and optionally appends them to a local CSV time series.",
+	Run: func(cmd *cobra.Command, args []string) {
+		breadth, err := home.New(nil).GetBreadth()
+		if err != nil {
+			utils.Err(err)
+		}
+
+		path := series
+		if path == "" && (appendTo || history) {
+			if path, err = home.DefaultBreadthPath(); err != nil {
+				utils.Err(err)
+			}
+		}
+		if appendTo {
+			if err = home.AppendBreadth(path, breadth); err != nil {
+				utils.Err(err)
+			}
+		}
+
+		df := breadth.DataFrame()
+		if history {
+			if df, err = home.LoadBreadth(path); err != nil {
+				utils.Err(err)
+			}
+		}
+		if err = utils.ExportData(df, outFile); err != nil {
+			utils.Err(err)
+		}
+	},
+}
+
+func init() {
+	// --section signals|patterns|... --signal "Top Gainers"
+	// -o <filename>
+	section = utils.NewEnum(append([]string{"all"}, home.Sections...), "all")
+	Cmd.Flags().VarP(section, "section", "s", "all|"+strings.Join(home.Sections, "|"))
+	Cmd.Flags().StringVar(&signal, "signal", "", "the tickers of one signal, i.e. \"Top Gainers\" or \"Channel Up\"")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+
+	// breadth --append --history --series <filename> -o <filename>
+	BreadthCmd.Flags().BoolVar(&appendTo, "append", false, "append the breadth to the time series")
+	BreadthCmd.Flags().BoolVar(&history, "history", false, "return the time series instead of the current breadth")
+	BreadthCmd.Flags().StringVar(&series, "series", "", "breadth time series CSV file, defaults to the user cache directory")
+	BreadthCmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	Cmd.AddCommand(BreadthCmd)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package maps
+
+import (
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/maps"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+	mapName *utils.Enum
+	period  *utils.Enum
+	level   int
+
+	// Cmd is the CLI subcommand for Finviz maps
+	Cmd = &cobra.Command{
+		Use:     "maps",
+		Aliases: []string{"map"},
+		Short:   "Finviz Maps",
+		Long: "Finviz Maps returns the tickers of a heat map with their groups, market cap weight and performance " +
+			"over a period, or the market cap weighted performance of the groups of one level, i.e. sectors.",
+		Run: func(cmd *cobra.Command, args []string) {
+			root, err := maps.New(nil).GetMap(mapName.Value, period.Value)
+			if err != nil {
+				utils.Err(err)
+			}
+
+			var df *dataframe.DataFrame
+			if level > 0 {
+				df, err = maps.GroupDataFrame(root, level)
+			} else {
+				df, err = maps.DataFrame(root, maps.LevelLookup[mapName.Value])
+			}
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+func init() {
+	// --map sp500 --period week --level 1
+	// -o <filename>
+	mapName = utils.NewEnum(maps.MapNames(), "sp500")
+	period = utils.NewEnum(maps.PeriodNames(), "day")
+	Cmd.Flags().VarP(mapName, "map", "m", strings.Join(maps.MapNames(), "|"))
+	Cmd.Flags().VarP(period, "period", "p", strings.Join(maps.PeriodNames(), "|"))
+	Cmd.Flags().IntVarP(&level, "level", "l", 0, "return the groups of a level instead of tickers, i.e. 1 for sectors and 2 for industries")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+}
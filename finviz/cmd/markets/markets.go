@@ -0,0 +1,48 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package markets
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/markets"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+
+	// FuturesCmd is the CLI subcommand for Finviz futures
+	FuturesCmd = newCmd("futures", "Finviz Futures", "Finviz Futures returns the last price, daily change and performance of futures.")
+
+	// ForexCmd is the CLI subcommand for Finviz forex
+	ForexCmd = newCmd("forex", "Finviz Forex", "Finviz Forex returns the last price, daily change and performance of currency pairs.")
+
+	// CryptoCmd is the CLI subcommand for Finviz crypto
+	CryptoCmd = newCmd("crypto", "Finviz Crypto", "Finviz Crypto returns the last price, daily change and performance of cryptocurrencies.")
+)
+
+// newCmd returns the subcommand of one of the markets.MarketLookup markets
+func newCmd(market, short, long string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   market,
+		Short: short,
+		Long:  long,
+		Run: func(cmd *cobra.Command, args []string) {
+			df, err := markets.New(nil).GetPerformance(market)
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+	// -o <filename>
+	cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	return cmd
+}
@@ -6,40 +6,211 @@
 package news
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
 	"github.com/spf13/cobra"
 
 	"github.com/d3an/finviz/news"
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/screener"
+	"github.com/d3an/finviz/universe"
 	"github.com/d3an/finviz/utils"
 )
 
 var (
-	outFile string
-	view    *utils.Enum
+	outFile  string
+	view     *utils.Enum
+	tickers  []string
+	sources  []string
+	newsType *utils.Enum
+	since    time.Duration
+	match    string
+	keywords []string
+
+	mentions  bool
+	watchlist []string
+
+	cluster    bool
+	dedupe     bool
+	similarity float64
+	window     time.Duration
+
+	sourcesFile string
+	saveSources bool
 
 	// Cmd is the CLI subcommand for Finviz news
 	Cmd = &cobra.Command{
 		Use:     "news",
 		Aliases: []string{"ns"},
 		Short:   "Finviz News",
-		Long:    "Finviz News returns the latest news.",
+		Long: "Finviz News returns the latest news, or the news of the tickers provided. " +
+			"Ticker news is limited to the articles listed on the quote pages, about the latest 100 per ticker.",
 		Run: func(cmd *cobra.Command, args []string) {
+			if sourcesFile == "" {
+				if path, err := news.DefaultSourcesPath(); err == nil {
+					sourcesFile = path
+				}
+			}
+			if sourcesFile != "" {
+				if err := news.Sources.LoadFile(sourcesFile); err != nil {
+					utils.Err(err)
+				}
+			}
+
+			filter, err := newFilter()
+			if err != nil {
+				utils.Err(err)
+			}
+
 			client := news.New(nil)
-			df, err := client.GetNews(view.Value)
+
+			var df *dataframe.DataFrame
+			if len(tickers) > 0 {
+				df, err = client.GetTickerNews(tickers)
+			} else {
+				df, err = client.GetNews(view.Value)
+			}
 			if err != nil {
 				utils.Err(err)
 			}
 
-			if err = utils.ExportData(df, outFile); err != nil {
+			if mentions || len(watchlist) > 0 {
+				if df, err = tagMentions(df); err != nil {
+					utils.Err(err)
+				}
+			}
+
+			if df, err = news.FilterNews(df, filter); err != nil {
+				utils.Err(err)
+			}
+
+			if cluster || dedupe {
+				options := news.ClusterOptions{Threshold: similarity, Window: window}
+				if dedupe {
+					df, err = news.DedupeNews(df, options)
+				} else {
+					df, err = news.ClusterNews(df, options)
+				}
+				if err != nil {
+					utils.Err(err)
+				}
+			}
+
+			for class, count := range news.Sources.Unknown() {
+				fmt.Printf("[WARNING]: unknown news source class '%s' on %d articles, run with '-v source --save-sources' to learn it\n", class, count)
+			}
+			if saveSources && sourcesFile != "" {
+				if err = news.Sources.SaveFile(sourcesFile); err != nil {
+					utils.Err(err)
+				}
+			}
+
+			if err = export(df); err != nil {
 				utils.Err(err)
 			}
 		},
 	}
 )
 
+func newFilter() (filter news.Filter, err error) {
+	if len(sources) > 0 {
+		if filter.Sources, err = news.ValidateSources(sources); err != nil {
+			return filter, err
+		}
+	}
+	if newsType.Value != "all" {
+		// Ticker news isn't split into news and blogs
+		if len(tickers) > 0 {
+			return filter, fmt.Errorf("error --type is not supported with --tickers, ticker news has no news type")
+		}
+		filter.Type = newsType.Value
+	}
+	if match != "" {
+		if filter.Match, err = regexp.Compile("(?i)" + match); err != nil {
+			return filter, err
+		}
+	}
+	if since > 0 {
+		filter.Since = time.Now().Add(-since)
+	}
+	filter.Keywords = keywords
+	filter.Tickers = watchlist
+	return filter, nil
+}
+
+// tagMentions tags the news with the tickers of the cached ticker universe mentioned in titles
+func tagMentions(df *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+	path, err := universe.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	u, err := universe.Open(screener.New(nil), path, universe.DefaultMaxAge)
+	if err != nil {
+		return nil, err
+	}
+	return news.TagMentions(df, news.NewMentionExtractor(u.Entries, news.DefaultAliases))
+}
+
+// export writes an RSS feed for .xml and .rss files, an Atom feed for .atom files, and otherwise exports the data
+func export(df *dataframe.DataFrame) error {
+	var write func(io.Writer, *dataframe.DataFrame, news.FeedInfo) error
+	switch filepath.Ext(outFile) {
+	case ".xml", ".rss":
+		write = news.WriteRSS
+	case ".atom":
+		write = news.WriteAtom
+	default:
+		return utils.ExportData(df, outFile)
+	}
+
+	info := news.DefaultFeedInfo
+	if len(tickers) > 0 {
+		info.Title = fmt.Sprintf("Finviz News: %s", strings.ToUpper(strings.Join(tickers, ", ")))
+		info.Link = fmt.Sprintf("%s?t=%s", quote.APIURL, strings.ToUpper(tickers[0]))
+	}
+
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	if err = write(f, df, info); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func init() {
 	// -v time|source
-	// -o <filename>
+	// -t aapl,amzn,tsla
+	// --source Reuters --type news|blog --since 2h --match "Fed" --keyword rates,inflation
+	// --mentions -w aapl,tsla
+	// --cluster|--dedupe --similarity 0.5 --window 3h
+	// --sources-file <filename> --save-sources
+	// -o <filename>, where .xml and .rss write an RSS feed and .atom writes an Atom feed
 	view = utils.NewEnum([]string{"time", "source"}, "time")
 	Cmd.Flags().VarP(view, "view", "v", "time|source")
-	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	Cmd.Flags().StringSliceVarP(&tickers, "tickers", "t", nil, "AAPL,GS,amzn")
+	Cmd.Flags().StringSliceVarP(&sources, "source", "s", nil, "Reuters,Bloomberg")
+	newsType = utils.NewEnum([]string{"all", "news", "blog"}, "all")
+	Cmd.Flags().Var(newsType, "type", "all|news|blog")
+	Cmd.Flags().DurationVar(&since, "since", 0, "2h")
+	Cmd.Flags().StringVarP(&match, "match", "m", "", "case-insensitive regular expression matched against titles")
+	Cmd.Flags().StringSliceVarP(&keywords, "keyword", "k", nil, "rates,inflation")
+	Cmd.Flags().BoolVar(&mentions, "mentions", false, "tag news with the tickers mentioned in titles, using the cached ticker universe")
+	Cmd.Flags().StringSliceVarP(&watchlist, "watchlist", "w", nil, "only news mentioning AAPL,TSLA")
+	Cmd.Flags().BoolVar(&cluster, "cluster", false, "group near-duplicate headlines, adding Cluster ID, Canonical and Alternate Sources columns")
+	Cmd.Flags().BoolVar(&dedupe, "dedupe", false, "keep only the canonical article of each group of near-duplicate headlines")
+	Cmd.Flags().Float64Var(&similarity, "similarity", news.DefaultClusterOptions.Threshold, "minimum share of title words for headlines to be near-duplicates")
+	Cmd.Flags().DurationVar(&window, "window", news.DefaultClusterOptions.Window, "maximum time between near-duplicate headlines")
+	Cmd.Flags().StringVar(&sourcesFile, "sources-file", "", "JSON file of news source classes, i.e. {\"is-7\": \"Bloomberg\"}")
+	Cmd.Flags().BoolVar(&saveSources, "save-sources", false, "save the news sources learned from the source view to the sources file")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json|xml|rss|atom)")
 }
@@ -0,0 +1,147 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package portfolio
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/portfolio"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+	email   string
+	session string
+	pid     int
+	shares  float64
+	price   float64
+	date    string
+
+	// Cmd is the CLI subcommand for Finviz portfolios
+	Cmd = &cobra.Command{
+		Use:   "portfolio",
+		Short: "Finviz Portfolio",
+		Long: "Finviz Portfolio lists, reads and edits the portfolios of a Finviz account. Sign in with --session or " +
+			"FINVIZ_SESSION, or with --email or FINVIZ_EMAIL and the FINVIZ_PASSWORD environment variable, so the " +
+			"password is kept out of the shell history.",
+		Run: func(cmd *cobra.Command, args []string) {
+			portfolios, err := signIn().GetPortfolios()
+			if err != nil {
+				utils.Err(err)
+			}
+			for _, p := range portfolios {
+				fmt.Printf("%d\t%s\n", p.ID, p.Name)
+			}
+		},
+	}
+
+	// HoldingsCmd is the CLI subcommand for the holdings of a portfolio
+	HoldingsCmd = &cobra.Command{
+		Use:   "holdings",
+		Short: "Finviz Portfolio Holdings",
+		Long:  "Finviz Portfolio Holdings returns the shares, cost, value and gain of the tickers of a portfolio.",
+		Run: func(cmd *cobra.Command, args []string) {
+			df, err := signIn().GetHoldings(pid)
+			if err != nil {
+				utils.Err(err)
+			}
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+
+	// AddCmd is the CLI subcommand for adding a position to a portfolio
+	AddCmd = &cobra.Command{
+		Use:   "add <ticker>",
+		Short: "Add a Finviz Portfolio Position",
+		Long:  "Add a Finviz Portfolio Position adds shares of a ticker, bought at a price on a date, to a portfolio.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			position := portfolio.Position{Ticker: args[0], Shares: shares, Price: price}
+			if date != "" {
+				var err error
+				if position.Date, err = time.ParseInLocation("2006-01-02", date, utils.Eastern); err != nil {
+					utils.Err(fmt.Sprintf("error parsing date '%s', expected YYYY-MM-DD", date))
+				}
+			}
+			if err := signIn().AddPosition(pid, position); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+
+	// RemoveCmd is the CLI subcommand for removing a position from a portfolio
+	RemoveCmd = &cobra.Command{
+		Use:   "remove <ticker>",
+		Short: "Remove a Finviz Portfolio Position",
+		Long:  "Remove a Finviz Portfolio Position removes every transaction of a ticker from a portfolio.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := signIn().RemovePosition(pid, args[0]); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+// signIn returns a client signed in with the flags, or else the environment variables. The password is only read
+// from FINVIZ_PASSWORD.
+func signIn() *portfolio.Client {
+	c := portfolio.New(nil)
+	if session == "" {
+		session = os.Getenv("FINVIZ_SESSION")
+	}
+	if session != "" {
+		if err := c.SetSession(session); err != nil {
+			utils.Err(err)
+		}
+		return c
+	}
+
+	if email == "" {
+		email = os.Getenv("FINVIZ_EMAIL")
+	}
+	password := os.Getenv("FINVIZ_PASSWORD")
+	if email == "" || password == "" {
+		utils.Err(utils.NotAuthenticatedError("error portfolios require --email and FINVIZ_PASSWORD, or --session"))
+	}
+	if err := c.Login(email, password); err != nil {
+		utils.Err(err)
+	}
+	return c
+}
+
+func init() {
+	// --email <email> --session <cookie>
+	Cmd.PersistentFlags().StringVar(&email, "email", "", "Finviz account email, signed in with FINVIZ_PASSWORD")
+	Cmd.PersistentFlags().StringVar(&session, "session", "", "value of a signed in browser's "+portfolio.SessionCookie+" cookie")
+
+	// holdings --pid <id> -o <filename>
+	HoldingsCmd.Flags().IntVarP(&pid, "pid", "p", 0, "portfolio id, as listed by the portfolio command")
+	HoldingsCmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+	_ = HoldingsCmd.MarkFlagRequired("pid")
+
+	// add <ticker> --pid <id> --shares <n> --price <n> --date YYYY-MM-DD
+	AddCmd.Flags().IntVarP(&pid, "pid", "p", 0, "portfolio id, as listed by the portfolio command")
+	AddCmd.Flags().Float64Var(&shares, "shares", 0, "number of shares")
+	AddCmd.Flags().Float64Var(&price, "price", 0, "price per share")
+	AddCmd.Flags().StringVar(&date, "date", "", "transaction date, YYYY-MM-DD, defaults to today")
+	_ = AddCmd.MarkFlagRequired("pid")
+	_ = AddCmd.MarkFlagRequired("shares")
+	_ = AddCmd.MarkFlagRequired("price")
+
+	// remove <ticker> --pid <id>
+	RemoveCmd.Flags().IntVarP(&pid, "pid", "p", 0, "portfolio id, as listed by the portfolio command")
+	_ = RemoveCmd.MarkFlagRequired("pid")
+
+	Cmd.AddCommand(HoldingsCmd, AddCmd, RemoveCmd)
+}
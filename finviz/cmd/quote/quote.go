@@ -6,15 +6,20 @@
 package quote
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/screener"
+	"github.com/d3an/finviz/universe"
 	"github.com/d3an/finviz/utils"
 )
 
 var (
-	outFile string
-	tickers []string
+	outFile  string
+	tickers  []string
+	validate bool
 
 	// Cmd is the CLI subcommand for Finviz news
 	Cmd = &cobra.Command{
@@ -24,10 +29,25 @@ var (
 		Long:    "Finviz Quotes returns the quotes for tickers provided.",
 		Run: func(cmd *cobra.Command, args []string) {
 			client := quote.New(nil)
+			if validate {
+				path, err := universe.DefaultPath()
+				if err != nil {
+					utils.Err(err)
+				}
+				u, err := universe.Open(screener.New(nil), path, universe.DefaultMaxAge)
+				if err != nil {
+					utils.Err(err)
+				}
+				client.SetValidator(u)
+			}
+
 			results, err := client.GetQuotes(tickers)
 			if err != nil {
 				utils.Err(err)
 			}
+			for _, warning := range results.Warnings {
+				fmt.Printf("[WARNING]: %s: %v\n", warning.Ticker, warning.Error)
+			}
 
 			if err = utils.ExportData(results.Data, outFile); err != nil {
 				utils.Err(err)
@@ -38,7 +58,9 @@ var (
 
 func init() {
 	// -t aapl,amzn,tsla
+	// --validate
 	// -o <filename>
 	Cmd.Flags().StringSliceVarP(&tickers, "tickers", "t", nil, "AAPL,GS,amzn")
+	Cmd.Flags().BoolVar(&validate, "validate", false, "reject tickers missing from the cached ticker universe")
 	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
 }
@@ -12,10 +12,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/d3an/finviz/finviz/cmd/calendar"
+	"github.com/d3an/finviz/finviz/cmd/compare"
 	"github.com/d3an/finviz/finviz/cmd/earnings"
+	"github.com/d3an/finviz/finviz/cmd/groups"
+	"github.com/d3an/finviz/finviz/cmd/home"
+	"github.com/d3an/finviz/finviz/cmd/maps"
+	"github.com/d3an/finviz/finviz/cmd/markets"
 	"github.com/d3an/finviz/finviz/cmd/news"
+	"github.com/d3an/finviz/finviz/cmd/portfolio"
 	"github.com/d3an/finviz/finviz/cmd/quote"
 	"github.com/d3an/finviz/finviz/cmd/screener"
+	"github.com/d3an/finviz/finviz/cmd/signals"
+	"github.com/d3an/finviz/finviz/cmd/universe"
 )
 
 var rootCmd = &cobra.Command{
@@ -40,4 +48,14 @@ func init() {
 	rootCmd.AddCommand(quote.Cmd)
 	rootCmd.AddCommand(calendar.Cmd)
 	rootCmd.AddCommand(earnings.Cmd)
+	rootCmd.AddCommand(compare.Cmd)
+	rootCmd.AddCommand(universe.Cmd)
+	rootCmd.AddCommand(home.Cmd)
+	rootCmd.AddCommand(groups.Cmd)
+	rootCmd.AddCommand(maps.Cmd)
+	rootCmd.AddCommand(markets.FuturesCmd)
+	rootCmd.AddCommand(markets.ForexCmd)
+	rootCmd.AddCommand(markets.CryptoCmd)
+	rootCmd.AddCommand(portfolio.Cmd)
+	rootCmd.AddCommand(signals.Cmd)
 }
@@ -0,0 +1,68 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package signals
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/screener"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile string
+	filters []string
+
+	// Cmd is the CLI subcommand for screener signals
+	Cmd = &cobra.Command{
+		Use:   "signals",
+		Short: "Finviz Screener Signals",
+		Long: "Finviz Screener Signals lists the technical, news, insider and chart pattern signals of the screener, " +
+			"and returns the stocks of a signal with the run subcommand.",
+	}
+
+	// ListCmd is the CLI subcommand listing the screener signals
+	ListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List Finviz Screener Signals",
+		Long:  "List Finviz Screener Signals returns the name, s query parameter and description of every signal.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := utils.ExportData(screener.SignalDataFrame(), outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+
+	// RunCmd is the CLI subcommand screening the stocks of a signal
+	RunCmd = &cobra.Command{
+		Use:   "run <signal>",
+		Short: "Run a Finviz Screener Signal",
+		Long: "Run a Finviz Screener Signal returns the overview of the stocks of a signal, i.e. topgainers, " +
+			"\"Channel Up\" or ta_unusualvolume, optionally narrowed by Finviz filters.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			df, err := screener.New(nil).RunSignal(args[0], filters...)
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if err = utils.ExportData(df, outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+func init() {
+	// list -o <filename>
+	ListCmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+
+	// run <signal> -f cap_large -f exch_nasd -o <filename>
+	RunCmd.Flags().StringSliceVarP(&filters, "filter", "f", nil, "Finviz filter, i.e. cap_large or exch_nasd|nyse")
+	RunCmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+
+	Cmd.AddCommand(ListCmd, RunCmd)
+}
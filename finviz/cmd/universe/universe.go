@@ -0,0 +1,99 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package universe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/d3an/finviz/screener"
+	"github.com/d3an/finviz/universe"
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	outFile  string
+	path     string
+	maxAge   time.Duration
+	refresh  bool
+	tickers  []string
+	sector   string
+	industry string
+	country  string
+
+	// Cmd is the CLI subcommand for the local ticker universe
+	Cmd = &cobra.Command{
+		Use:     "universe",
+		Aliases: []string{"u"},
+		Short:   "Finviz Ticker Universe",
+		Long: "Finviz Ticker Universe returns every listed ticker with its sector, industry, country and exchange. " +
+			"The universe is built from the screener and cached locally until it is older than --max-age.",
+		Run: func(cmd *cobra.Command, args []string) {
+			u, err := open()
+			if err != nil {
+				utils.Err(err)
+			}
+
+			if len(tickers) > 0 {
+				var entries []universe.Entry
+				for _, ticker := range tickers {
+					entry, exists := u.Lookup(ticker)
+					if !exists {
+						utils.Err(utils.TickerNotFoundError(fmt.Sprintf("error ticker '%s' not found in universe", ticker)))
+					}
+					entries = append(entries, entry)
+				}
+				u = universe.New(entries, u.UpdatedAt)
+			}
+			if sector != "" {
+				u = universe.New(u.BySector(sector), u.UpdatedAt)
+			}
+			if industry != "" {
+				u = universe.New(u.ByIndustry(industry), u.UpdatedAt)
+			}
+			if country != "" {
+				u = universe.New(u.ByCountry(country), u.UpdatedAt)
+			}
+
+			if err = utils.ExportData(universe.DataFrame(u.Entries), outFile); err != nil {
+				utils.Err(err)
+			}
+		},
+	}
+)
+
+// open loads the cached universe, rebuilding it when stale or when --refresh is set
+func open() (*universe.Universe, error) {
+	if path == "" {
+		var err error
+		if path, err = universe.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	age := maxAge
+	if refresh {
+		age = 0
+	}
+	return universe.Open(screener.New(nil), path, age)
+}
+
+func init() {
+	// -t aapl,amzn,tsla
+	// --sector Technology --industry "Consumer Electronics" --country USA
+	// --refresh --max-age 24h --path <filename>
+	// -o <filename>
+	Cmd.Flags().StringSliceVarP(&tickers, "tickers", "t", nil, "AAPL,GS,amzn")
+	Cmd.Flags().StringVar(&sector, "sector", "", "Technology")
+	Cmd.Flags().StringVar(&industry, "industry", "", "Consumer Electronics")
+	Cmd.Flags().StringVar(&country, "country", "", "USA")
+	Cmd.Flags().BoolVar(&refresh, "refresh", false, "rebuild the universe from the screener")
+	Cmd.Flags().DurationVar(&maxAge, "max-age", universe.DefaultMaxAge, "rebuild the universe when older than this")
+	Cmd.Flags().StringVar(&path, "path", "", "universe.json (defaults to the user cache directory)")
+	Cmd.Flags().StringVarP(&outFile, "outfile", "o", "", "output.(csv|json)")
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package groups
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com/groups.ashx"
+)
+
+var (
+	once     sync.Once
+	instance *Client
+)
+
+type Config struct {
+	userAgent string
+	recorder  *recorder.Recorder
+}
+
+type Client struct {
+	*http.Client
+	config Config
+}
+
+func New(config *Config) *Client {
+	once.Do(func() {
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+		}
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		}
+		if config != nil {
+			instance = &Client{Client: client, config: *config}
+		}
+		instance = &Client{
+			Client: client,
+			config: Config{userAgent: uarand.GetRandom()},
+		}
+	})
+
+	return instance
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.config.userAgent)
+	return c.Client.Do(req)
+}
+
+// Groupings lists the ways stocks are grouped. Industry and capitalization groups can be limited to one of the Sectors.
+var Groupings = []string{"sector", "industry", "country", "capitalization"}
+
+// Sectors lists the sectors industry and capitalization groups can be limited to
+var Sectors = []string{
+	"basicmaterials", "communicationservices", "consumercyclical", "consumerdefensive", "energy", "financial",
+	"healthcare", "industrials", "realestate", "technology", "utilities",
+}
+
+// ViewLookup maps the group views to their v query parameter
+var ViewLookup = map[string]int{
+	"overview":    110,
+	"valuation":   120,
+	"performance": 140,
+	"custom":      152,
+}
+
+// Orders lists the columns groups can be ordered by. A leading "-" orders them descending, i.e. "-change".
+var Orders = []string{
+	"name", "count", "marketcap", "pe", "fpe", "peg", "ps", "pb", "pc", "pfcf", "dividendyield", "eps5years",
+	"estltgrowth", "sales5years", "shortinterestshare", "perf1w", "perf4w", "perf13w", "perf26w", "perf52w",
+	"perfytd", "recom", "averagevolume", "relativevolume", "change", "volume",
+}
+
+// CustomColumns maps the columns of the custom view to their c query parameter
+var CustomColumns = map[string]int{
+	"No.": 0, "Name": 1, "Market Cap": 2, "P/E": 3, "Fwd P/E": 4, "PEG": 5, "P/S": 6, "P/B": 7, "P/C": 8,
+	"P/FCF": 9, "Dividend": 10, "EPS past 5Y": 11, "EPS next 5Y": 12, "Sales past 5Y": 13, "Float Short": 14,
+	"Perf Week": 15, "Perf Month": 16, "Perf Quart": 17, "Perf Half": 18, "Perf Year": 19, "Perf YTD": 20,
+	"Recom": 21, "Avg Volume": 22, "Rel Volume": 23, "Change": 24, "Volume": 25, "Stocks": 26,
+}
+
+// Query selects the groups and columns of a groups page
+type Query struct {
+	// By is one of the Groupings, defaulting to sector
+	By string
+	// Sector limits industry and capitalization groups to one of the Sectors
+	Sector string
+	// View is one of the ViewLookup keys, defaulting to overview
+	View string
+	// Order is one of the Orders, defaulting to name
+	Order string
+	// Columns are the CustomColumns of the custom view, in the order listed, defaulting to all of them
+	Columns []string
+}
+
+// GetGroups returns a DataFrame of the groups selected by the query
+func (c *Client) GetGroups(q Query) (*dataframe.DataFrame, error) {
+	url, err := GenerateURL(q)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting groups, status code: '%d', body: '%s'", resp.StatusCode, string(body))
+	}
+
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := Scrape(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	df := dataframe.LoadRecords(results)
+	return utils.CleanFinvizDataFrame(&df), nil
+}
+
+// GenerateURL returns the groups page URL of the query
+func GenerateURL(q Query) (string, error) {
+	by := strings.ToLower(q.By)
+	if by == "" {
+		by = "sector"
+	}
+	if !utils.Contains(Groupings, by) {
+		return "", fmt.Errorf("error grouping '%s' not found, expected one of: %s", q.By, strings.Join(Groupings, ", "))
+	}
+	query := "g=" + by
+
+	if q.Sector != "" {
+		sector := strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(q.Sector))
+		if by != "industry" && by != "capitalization" {
+			return "", fmt.Errorf("error only industry and capitalization groups can be limited to a sector")
+		}
+		if !utils.Contains(Sectors, sector) {
+			return "", fmt.Errorf("error sector '%s' not found, expected one of: %s", q.Sector, strings.Join(Sectors, ", "))
+		}
+		query += "&sg=" + sector
+	}
+
+	view := strings.ToLower(q.View)
+	if view == "" {
+		view = "overview"
+	}
+	v, exists := ViewLookup[view]
+	if !exists {
+		return "", fmt.Errorf("error view '%s' not found, expected one of: %s", q.View, strings.Join(ViewNames(), ", "))
+	}
+	query += fmt.Sprintf("&v=%d", v)
+
+	order := strings.ToLower(q.Order)
+	if order == "" {
+		order = "name"
+	}
+	if !utils.Contains(Orders, strings.TrimPrefix(order, "-")) {
+		return "", fmt.Errorf("error order '%s' not found, expected one of: %s", q.Order, strings.Join(Orders, ", "))
+	}
+	query += "&o=" + order
+
+	if view == "custom" {
+		// Columns are listed in the order requested, or in the page's order by default
+		var indexes []int
+		if len(q.Columns) == 0 {
+			for _, index := range CustomColumns {
+				indexes = append(indexes, index)
+			}
+			sort.Ints(indexes)
+		}
+		for _, name := range q.Columns {
+			index, exists := customColumn(name)
+			if !exists {
+				return "", fmt.Errorf("error custom column '%s' not found", name)
+			}
+			indexes = append(indexes, index)
+		}
+		numbers := make([]string, len(indexes))
+		for i, index := range indexes {
+			numbers[i] = strconv.Itoa(index)
+		}
+		query += "&c=" + strings.Join(numbers, ",")
+	} else if len(q.Columns) > 0 {
+		return "", fmt.Errorf("error columns can only be selected in the custom view")
+	}
+
+	return fmt.Sprintf("%s?%s", APIURL, query), nil
+}
+
+// ViewNames returns the names of the group views, sorted
+func ViewNames() []string {
+	names := make([]string, 0, len(ViewLookup))
+	for name := range ViewLookup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customColumn returns the c query parameter of a custom column, ignoring case
+func customColumn(name string) (int, bool) {
+	for column, index := range CustomColumns {
+		if strings.EqualFold(column, name) {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// Scrape returns the rows of the groups table, headers first
+func Scrape(doc *goquery.Document) ([][]string, error) {
+	header := doc.Find("tr").FilterFunction(func(i int, row *goquery.Selection) bool {
+		return row.ChildrenFiltered("td").FilterFunction(func(j int, cell *goquery.Selection) bool {
+			return strings.TrimSpace(cell.Text()) == "Name"
+		}).Length() > 0
+	}).Last()
+	if header.Length() == 0 {
+		return nil, fmt.Errorf("error groups table not found")
+	}
+
+	var headers []string
+	header.ChildrenFiltered("td").Each(func(i int, cell *goquery.Selection) {
+		headers = append(headers, strings.TrimSpace(cell.Text()))
+	})
+
+	var groupDataSlice []map[string]interface{}
+	header.NextAll().Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() != len(headers) {
+			return
+		}
+		rawGroupData := make(map[string]interface{})
+		cells.Each(func(j int, cell *goquery.Selection) {
+			rawGroupData[headers[j]] = expandNumber(headers[j], strings.TrimSpace(cell.Text()))
+		})
+		groupDataSlice = append(groupDataSlice, rawGroupData)
+	})
+
+	return utils.GenerateRows(headers, groupDataSlice)
+}
+
+// expandNumber writes the abbreviated volumes of groups, i.e. "1.23B", in full as CleanFinvizDataFrame expects
+func expandNumber(header, value string) string {
+	if header != "Volume" || value == "" {
+		return value
+	}
+	multiple := map[byte]float64{'K': 1e3, 'M': 1e6, 'B': 1e9}[value[len(value)-1]]
+	if multiple == 0 {
+		return value
+	}
+	number, err := strconv.ParseFloat(value[:len(value)-1], 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatInt(int64(number*multiple), 10)
+}
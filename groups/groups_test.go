@@ -0,0 +1,67 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package groups
+
+import (
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils"
+)
+
+func TestGenerateURL(t *testing.T) {
+	values := []struct {
+		query    Query
+		expected string
+	}{
+		{Query{}, APIURL + "?g=sector&v=110&o=name"},
+		{Query{By: "industry", View: "performance", Order: "-perf1w"}, APIURL + "?g=industry&v=140&o=-perf1w"},
+		{Query{By: "capitalization", Sector: "Basic Materials", View: "valuation"}, APIURL + "?g=capitalization&sg=basicmaterials&v=120&o=name"},
+		{Query{By: "country", View: "custom", Columns: []string{"change", "Name", "No."}}, APIURL + "?g=country&v=152&o=name&c=24,1,0"},
+	}
+	for _, v := range values {
+		url, err := GenerateURL(v.query)
+		require.Nil(t, err)
+		require.Equal(t, v.expected, url)
+	}
+
+	for _, q := range []Query{{By: "exchange"}, {By: "sector", Sector: "energy"}, {View: "charts"}, {Order: "ticker"}, {Columns: []string{"Name"}}, {View: "custom", Columns: []string{"Ticker"}}} {
+		_, err := GenerateURL(q)
+		require.NotNil(t, err, q)
+	}
+}
+
+// TestScrape parses a hand-written groups table, since no groups page is recorded. The ViewLookup, CustomColumns
+// and Orders parameters are not verified against a recording either.
+func TestScrape(t *testing.T) {
+	doc, err := utils.GenerateDocument(`<html><body><table width="100%" cellpadding="3" cellspacing="1" border="0" bgcolor="#d3d3d3">
+<tr valign="middle" align="center"><td class="table-top">No.</td><td class="table-top-s">Name</td><td class="table-top">Perf Week</td><td class="table-top">Perf YTD</td><td class="table-top">Recom</td><td class="table-top">Avg Volume</td><td class="table-top">Change</td><td class="table-top">Volume</td></tr>
+<tr class="table-light-row-cp"><td class="body-table" align="right">1</td><td class="body-table"><a href="screener.ashx?v=111&f=sec_basicmaterials" class="tab-link">Basic Materials</a></td><td class="body-table" align="right"><span class="is-green">2.15%</span></td><td class="body-table" align="right"><span class="is-red">-1.04%</span></td><td class="body-table" align="right">2.12</td><td class="body-table" align="right">437.43M</td><td class="body-table" align="right"><span class="is-green">0.75%</span></td><td class="body-table" align="right">392,564,313</td></tr>
+<tr class="table-dark-row-cp"><td class="body-table" align="right">2</td><td class="body-table"><a href="screener.ashx?v=111&f=sec_technology" class="tab-link">Technology</a></td><td class="body-table" align="right"><span class="is-red">-3.08%</span></td><td class="body-table" align="right"><span class="is-red">-7.86%</span></td><td class="body-table" align="right">1.96</td><td class="body-table" align="right">2.41B</td><td class="body-table" align="right"><span class="is-red">-2.42%</span></td><td class="body-table" align="right">2.74B</td></tr>
+</table></body></html>`)
+	require.Nil(t, err)
+
+	results, err := Scrape(doc)
+	require.Nil(t, err)
+	df := dataframe.LoadRecords(results)
+	df = *utils.CleanFinvizDataFrame(&df)
+	require.Equal(t, []string{"No.", "Name", "Perf Week", "Perf YTD", "Recom", "Avg Volume", "Change", "Volume"}, df.Names())
+	require.Equal(t, []string{"Basic Materials", "Technology"}, df.Col("Name").Records())
+	require.InDelta(t, -0.0308, df.Col("Perf Week").Elem(1).Float(), 1e-9)
+	volumes, err := df.Col("Volume").Int()
+	require.Nil(t, err)
+	require.Equal(t, []int{392564313, 2740000000}, volumes)
+	averageVolume, err := df.Col("Avg Volume").Elem(0).Int()
+	require.Nil(t, err)
+	require.Equal(t, 437430000, averageVolume)
+
+	doc, err = utils.GenerateDocument("<html><body><p>Access denied</p></body></html>")
+	require.Nil(t, err)
+	_, err = Scrape(doc)
+	require.NotNil(t, err)
+}
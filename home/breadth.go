@@ -0,0 +1,214 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package home
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// BreadthHeaders are the columns of a breadth time series
+var BreadthHeaders = []string{
+	"Date",
+	"Advancing", "Declining", "Advancing %", "Declining %",
+	"New High", "New Low", "New High %", "New Low %",
+	"Above SMA50", "Below SMA50", "Above SMA50 %", "Below SMA50 %",
+	"Above SMA200", "Below SMA200", "Above SMA200 %", "Below SMA200 %",
+}
+
+// BreadthBar is one of the homepage breadth bars, i.e. advancing vs declining issues. The percentages are fractions
+// of all issues, so advancing and declining do not add up to one when issues are unchanged.
+type BreadthBar struct {
+	Left         int
+	Right        int
+	LeftPercent  float64
+	RightPercent float64
+}
+
+// Breadth are the market breadth statistics of NYSE, Nasdaq and AMEX issues shown on the homepage
+type Breadth struct {
+	Fetched          time.Time
+	AdvancingDecline BreadthBar
+	NewHighLow       BreadthBar
+	SMA50            BreadthBar
+	SMA200           BreadthBar
+}
+
+// breadthTitles identify the breadth bars by their tooltips
+var breadthTitles = []string{"Advancing / Declining", "New High / New Low", "Above SMA50 / Below SMA50", "Above SMA200 / Below SMA200"}
+
+// GetBreadth returns the market breadth statistics of the homepage
+func (c *Client) GetBreadth() (*Breadth, error) {
+	doc, fetched, err := c.fetch(APIURL)
+	if err != nil {
+		return nil, err
+	}
+	return ScrapeBreadth(doc, fetched)
+}
+
+// ScrapeBreadth parses the breadth bars of the homepage, fetched at the provided time
+func ScrapeBreadth(doc *goquery.Document, fetched time.Time) (*Breadth, error) {
+	b := &Breadth{Fetched: fetched}
+	bars := []*BreadthBar{&b.AdvancingDecline, &b.NewHighLow, &b.SMA50, &b.SMA200}
+	found := make([]bool, len(bars))
+
+	var err error
+	doc.Find("div.market-stats").Each(func(i int, stats *goquery.Selection) {
+		if err != nil {
+			return
+		}
+		title := stats.AttrOr("title", "")
+		for j, name := range breadthTitles {
+			if !strings.Contains(title, "<b>"+name+"</b>") {
+				continue
+			}
+			values := []string{
+				stats.Find(".market-stats_labels_left span").Text(),
+				stats.Find(".market-stats_labels_right span").Text(),
+				stats.Find(".market-stats_bar_left-percent").Text(),
+				stats.Find(".market-stats_bar_right-percent").Text(),
+			}
+			bar := bars[j]
+			if bar.Left, err = strconv.Atoi(strings.TrimSpace(values[0])); err != nil {
+				err = fmt.Errorf("error parsing breadth '%s': %v", name, err)
+				return
+			}
+			if bar.Right, err = strconv.Atoi(strings.TrimSpace(values[1])); err != nil {
+				err = fmt.Errorf("error parsing breadth '%s': %v", name, err)
+				return
+			}
+			if bar.LeftPercent, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(values[2]), "%"), 64); err != nil {
+				err = fmt.Errorf("error parsing breadth '%s': %v", name, err)
+				return
+			}
+			if bar.RightPercent, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(values[3]), "%"), 64); err != nil {
+				err = fmt.Errorf("error parsing breadth '%s': %v", name, err)
+				return
+			}
+			bar.LeftPercent /= 100
+			bar.RightPercent /= 100
+			found[j] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, ok := range found {
+		if !ok {
+			return nil, fmt.Errorf("error homepage breadth '%s' not found", breadthTitles[i])
+		}
+	}
+	return b, nil
+}
+
+// Record returns the breadth as a row of a time series, in the order of BreadthHeaders, dated in Eastern time
+func (b *Breadth) Record() []string {
+	record := []string{b.Fetched.In(utils.Eastern).Format(time.RFC3339)}
+	for _, bar := range []BreadthBar{b.AdvancingDecline, b.NewHighLow, b.SMA50, b.SMA200} {
+		record = append(record,
+			strconv.Itoa(bar.Left), strconv.Itoa(bar.Right),
+			strconv.FormatFloat(bar.LeftPercent, 'f', -1, 64), strconv.FormatFloat(bar.RightPercent, 'f', -1, 64),
+		)
+	}
+	return record
+}
+
+// DataFrame returns the breadth as a single row DataFrame with the BreadthHeaders columns
+func (b *Breadth) DataFrame() *dataframe.DataFrame {
+	df := dataframe.LoadRecords([][]string{BreadthHeaders, b.Record()}, dataframe.WithTypes(map[string]series.Type{"Date": series.String}))
+	return &df
+}
+
+// DefaultBreadthPath returns the CSV file the CLI appends breadth statistics to
+func DefaultBreadthPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "finviz", "breadth.csv"), nil
+}
+
+// AppendBreadth appends the breadth to a CSV time series, creating it with the BreadthHeaders when missing.
+// Statistics fetched at the same time as the last row are not appended twice.
+func AppendBreadth(path string, b *Breadth) error {
+	records, err := readBreadth(path)
+	if err != nil {
+		return err
+	}
+	record := b.Record()
+	if n := len(records); n > 1 && records[n-1][0] == record[0] {
+		return nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if len(records) == 0 {
+		if err = w.Write(BreadthHeaders); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err = w.Write(record); err != nil {
+		f.Close()
+		return err
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadBreadth returns a CSV time series written by AppendBreadth, oldest first
+func LoadBreadth(path string) (*dataframe.DataFrame, error) {
+	records, err := readBreadth(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("error breadth time series '%s' is empty", path)
+	}
+	df := dataframe.LoadRecords(records, dataframe.WithTypes(map[string]series.Type{"Date": series.String}))
+	return &df, df.Error()
+}
+
+// readBreadth returns the records of a breadth time series, or none when the file is missing
+func readBreadth(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading breadth time series '%s': %v", path, err)
+	}
+	if len(records) > 0 && strings.Join(records[0], ",") != strings.Join(BreadthHeaders, ",") {
+		return nil, fmt.Errorf("error breadth time series '%s' does not have the expected columns", path)
+	}
+	return records, nil
+}
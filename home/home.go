@@ -0,0 +1,106 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package home
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com"
+)
+
+var (
+	once     sync.Once
+	instance *Client
+)
+
+type Config struct {
+	userAgent string
+	recorder  *recorder.Recorder
+}
+
+type Client struct {
+	*http.Client
+	config Config
+}
+
+func New(config *Config) *Client {
+	once.Do(func() {
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+		}
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		}
+		if config != nil {
+			instance = &Client{Client: client, config: *config}
+		}
+		instance = &Client{
+			Client: client,
+			config: Config{userAgent: uarand.GetRandom()},
+		}
+	})
+
+	return instance
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.config.userAgent)
+	return c.Client.Do(req)
+}
+
+// GetHome returns every table of the Finviz homepage
+func (c *Client) GetHome() (*Results, error) {
+	doc, fetched, err := c.fetch(APIURL)
+	if err != nil {
+		return nil, err
+	}
+	return Scrape(doc, fetched)
+}
+
+func (c *Client) fetch(url string) (*goquery.Document, time.Time, error) {
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("error getting homepage, status code: '%d', body: '%s'", resp.StatusCode, string(body))
+	}
+
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return doc, utils.FetchTime(resp), nil
+}
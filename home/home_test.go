@@ -0,0 +1,140 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package home
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils"
+	"github.com/d3an/finviz/utils/test"
+)
+
+func newTestClient(config *Config) *Client {
+	return &Client{
+		Client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: test.AddHeaderTransport(config.recorder),
+		},
+		config: *config,
+	}
+}
+
+func TestGetHome(t *testing.T) {
+	r, err := recorder.New(test.HomepageCassette)
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	results, err := client.GetHome()
+	require.Nil(t, err)
+	require.Equal(t, "2022-01-14T02:39:43Z", results.Fetched.UTC().Format(time.RFC3339))
+	require.NotNil(t, results.Breadth)
+
+	require.Equal(t, 38, results.Signals.Nrow())
+	gainers, err := results.Signal(TopGainers)
+	require.Nil(t, err)
+	require.Equal(t, []string{"SGLY", "MDVL", "BBIG", "DAVE", "GMVD", "ZKIN"}, gainers.Col("Ticker").Records())
+	require.InDelta(t, 0.2588, gainers.Col("Change").Elem(0).Float(), 1e-9)
+	volume, err := gainers.Col("Volume").Elem(0).Int()
+	require.Nil(t, err)
+	require.Equal(t, 4806327, volume)
+	upgrades, err := results.Signal(Upgrades)
+	require.Nil(t, err)
+	require.Equal(t, []string{"BAND"}, upgrades.Col("Ticker").Records())
+	_, err = results.Signal("Cup and Handle")
+	require.IsType(t, utils.ErrorSignalNotFound(""), err)
+
+	trendline, err := results.Signal("Trendline Support")
+	require.Nil(t, err)
+	require.Equal(t, []string{"VO", "FMF", "SDPI", "SMCP"}, trendline.Col("Ticker").Records())
+
+	require.Equal(t, "Biden picks Raskin for top Fed regulator role and two governors", results.Headlines.Col("Article Title").Records()[0])
+	require.Equal(t, "2022-01-13T21:39:00-05:00", results.Headlines.Col("Article Date").Records()[0])
+	require.Equal(t, "Bloomberg", results.Headlines.Col("Source Name").Records()[1])
+
+	require.Equal(t, "MSFT", results.MajorNews.Col("Ticker").Records()[0])
+	require.InDelta(t, -0.0423, results.MajorNews.Col("Change").Elem(0).Float(), 1e-9)
+
+	require.Equal(t, []string{"2022-01-12", "after", "KBH"}, results.Earnings.Records()[1])
+
+	require.Equal(t, "Crude Oil", results.Futures.Col("Name").Records()[0])
+	require.Equal(t, 81.71, results.Futures.Col("Last").Elem(0).Float())
+	require.Equal(t, "EUR/USD", results.Forex.Col("Name").Records()[0])
+	require.InDelta(t, 0.0009, results.Forex.Col("Change %").Elem(0).Float(), 1e-9)
+
+	require.Equal(t, []string{"INGN", "McFarland Loren L", "Director", "Jan 11", "Option Exercise", "8.370000", "2083", "17435"}, results.InsiderTrading.Records()[1])
+	require.Equal(t, []string{"ASAN", "Moskovitz Dustin A.", "Jan 07", "Buy", "31018860"}, results.TopInsiderTrading.Records()[1])
+
+	for _, section := range Sections {
+		df, err := results.Section(section)
+		require.Nil(t, err)
+		require.Greater(t, df.Nrow(), 0, section)
+	}
+}
+
+func TestScrapeWithoutBreadth(t *testing.T) {
+	r, err := recorder.New(test.HomepageCassette)
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	doc, fetched, err := client.fetch(APIURL)
+	require.Nil(t, err)
+	doc.Find("div.market-stats").Remove()
+
+	results, err := Scrape(doc, fetched)
+	require.Nil(t, err)
+	require.Nil(t, results.Breadth)
+	require.Equal(t, 38, results.Signals.Nrow())
+}
+
+func TestBreadth(t *testing.T) {
+	r, err := recorder.New(test.HomepageCassette)
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+	breadth, err := client.GetBreadth()
+	require.Nil(t, err)
+	require.Equal(t, BreadthBar{Left: 2654, Right: 5588, LeftPercent: 0.309, RightPercent: 0.65}, breadth.AdvancingDecline)
+	require.Equal(t, BreadthBar{Left: 335, Right: 463, LeftPercent: 0.42, RightPercent: 0.58}, breadth.NewHighLow)
+	require.Equal(t, 3231, breadth.SMA50.Left)
+	require.Equal(t, 5018, breadth.SMA200.Right)
+
+	path := filepath.Join(t.TempDir(), "breadth.csv")
+	require.Nil(t, AppendBreadth(path, breadth))
+	// Statistics of the same fetch are appended once
+	require.Nil(t, AppendBreadth(path, breadth))
+	later := *breadth
+	later.Fetched = later.Fetched.Add(24 * time.Hour)
+	later.AdvancingDecline.Left = 4000
+	require.Nil(t, AppendBreadth(path, &later))
+
+	df, err := LoadBreadth(path)
+	require.Nil(t, err)
+	require.Equal(t, BreadthHeaders, df.Names())
+	require.Equal(t, []string{"2022-01-13T21:39:43-05:00", "2022-01-14T21:39:43-05:00"}, df.Col("Date").Records())
+	advancing, err := df.Col("Advancing").Elem(1).Int()
+	require.Nil(t, err)
+	require.Equal(t, 4000, advancing)
+	require.Equal(t, 0.376, df.Col("Above SMA50 %").Elem(0).Float())
+}
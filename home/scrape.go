@@ -0,0 +1,357 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package home
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/earnings"
+	"github.com/d3an/finviz/news"
+	"github.com/d3an/finviz/utils"
+)
+
+// Homepage signals, listed beside the tickers of the signal tables
+const (
+	TopGainers     = "Top Gainers"
+	TopLosers      = "Top Losers"
+	NewHigh        = "New High"
+	NewLow         = "New Low"
+	Overbought     = "Overbought"
+	Oversold       = "Oversold"
+	UnusualVolume  = "Unusual Volume"
+	MostVolatile   = "Most Volatile"
+	MostActive     = "Most Active"
+	Upgrades       = "Upgrades"
+	Downgrades     = "Downgrades"
+	EarningsBefore = "Earnings Before"
+	EarningsAfter  = "Earnings After"
+	InsiderBuying  = "Insider Buying"
+	InsiderSelling = "Insider Selling"
+)
+
+// Sections lists the homepage tables, in page order
+var Sections = []string{"signals", "patterns", "headlines", "major-news", "earnings", "futures", "forex", "insider-trading", "top-insider-trading"}
+
+// Results are the tables of the Finviz homepage
+type Results struct {
+	Fetched time.Time
+	// Breadth is nil when the breadth bars cannot be parsed, so a change to them doesn't hide the other sections
+	Breadth *Breadth
+	// Signals lists the tickers of each homepage signal, i.e. TopGainers, with their last price, change and volume
+	Signals *dataframe.DataFrame
+	// Patterns lists the tickers of each chart pattern signal, i.e. "Channel Up"
+	Patterns *dataframe.DataFrame
+	// Headlines has the columns of news.Client.GetNews results
+	Headlines *dataframe.DataFrame
+	MajorNews *dataframe.DataFrame
+	Earnings  *dataframe.DataFrame
+	Futures   *dataframe.DataFrame
+	Forex     *dataframe.DataFrame
+	// InsiderTrading lists the latest insider transactions, and TopInsiderTrading the largest recent ones
+	InsiderTrading    *dataframe.DataFrame
+	TopInsiderTrading *dataframe.DataFrame
+}
+
+// Section returns one of the Sections
+func (r *Results) Section(name string) (*dataframe.DataFrame, error) {
+	switch name {
+	case "signals":
+		return r.Signals, nil
+	case "patterns":
+		return r.Patterns, nil
+	case "headlines":
+		return r.Headlines, nil
+	case "major-news":
+		return r.MajorNews, nil
+	case "earnings":
+		return r.Earnings, nil
+	case "futures":
+		return r.Futures, nil
+	case "forex":
+		return r.Forex, nil
+	case "insider-trading":
+		return r.InsiderTrading, nil
+	case "top-insider-trading":
+		return r.TopInsiderTrading, nil
+	default:
+		return nil, fmt.Errorf("error homepage section '%s' not found, expected one of: %s", name, strings.Join(Sections, ", "))
+	}
+}
+
+// Signal returns the rows of Signals listing a signal, i.e. TopGainers, or of Patterns listing a chart pattern
+func (r *Results) Signal(signal string) (*dataframe.DataFrame, error) {
+	for _, df := range []*dataframe.DataFrame{r.Signals, r.Patterns} {
+		var indexes []int
+		for i, name := range df.Col("Signal").Records() {
+			if strings.EqualFold(name, signal) {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) > 0 {
+			result := df.Subset(indexes)
+			return &result, result.Error()
+		}
+	}
+	return nil, utils.ErrorSignalNotFound(fmt.Sprintf("error signal '%s' not listed on the homepage", signal))
+}
+
+// Scrape parses the tables of the homepage, fetched at the provided time
+func Scrape(doc *goquery.Document, fetched time.Time) (*Results, error) {
+	if doc.Find("#signals_1").Length() == 0 {
+		return nil, fmt.Errorf("error homepage signal tables not found")
+	}
+
+	r := &Results{Fetched: fetched}
+	r.Breadth, _ = ScrapeBreadth(doc, fetched)
+
+	var err error
+	if r.Signals, err = scrapeSignals(doc); err != nil {
+		return nil, err
+	}
+	if r.Patterns, err = scrapePatterns(doc); err != nil {
+		return nil, err
+	}
+	if r.Headlines, err = scrapeHeadlines(doc, fetched); err != nil {
+		return nil, err
+	}
+	if r.MajorNews, err = scrapeMajorNews(doc); err != nil {
+		return nil, err
+	}
+	if r.Earnings, err = scrapeEarnings(doc, fetched); err != nil {
+		return nil, err
+	}
+	if r.Futures, err = scrapeQuotes(doc, "Futures"); err != nil {
+		return nil, err
+	}
+	if r.Forex, err = scrapeQuotes(doc, "Forex & Bonds"); err != nil {
+		return nil, err
+	}
+	if r.InsiderTrading, r.TopInsiderTrading, err = scrapeInsiderTrading(doc); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func scrapeSignals(doc *goquery.Document) (*dataframe.DataFrame, error) {
+	var rows []map[string]interface{}
+	doc.Find("#signals_1, #signals_2").Find("tr").Not(".t-home-table-top").Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() < 6 {
+			return
+		}
+		rows = append(rows, map[string]interface{}{
+			"Ticker": text(cells.Eq(0)),
+			"Last":   text(cells.Eq(1)),
+			"Change": percent(text(cells.Eq(2))),
+			"Volume": number(text(cells.Eq(3))),
+			"Signal": text(cells.Eq(5)),
+		})
+	})
+	return load([]string{"Ticker", "Last", "Change", "Volume", "Signal"}, rows, "Ticker", "Signal")
+}
+
+func scrapePatterns(doc *goquery.Document) (*dataframe.DataFrame, error) {
+	var rows []map[string]interface{}
+	tablesWithHeader(doc, "Tickers").Find("tr").Not(":first-child").Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		// Pattern names abbreviate words on small screens, i.e. "Trendline" as "TL"
+		signalCell := cells.Last().Clone()
+		signalCell.Find(".signal-small-screen").Remove()
+		signal := text(signalCell)
+		cells.Find("a.tab-link").Each(func(j int, link *goquery.Selection) {
+			rows = append(rows, map[string]interface{}{"Ticker": text(link), "Signal": signal})
+		})
+	})
+	return load([]string{"Ticker", "Signal"}, rows, "Ticker", "Signal")
+}
+
+func scrapeHeadlines(doc *goquery.Document, fetched time.Time) (*dataframe.DataFrame, error) {
+	var rows []map[string]interface{}
+	var err error
+	doc.Find("td.nn-date").Each(func(i int, dateCell *goquery.Selection) {
+		if err != nil {
+			return
+		}
+		row := dateCell.Parent()
+		var date time.Time
+		if date, err = utils.ParseNewsDate(text(dateCell), fetched); err != nil {
+			return
+		}
+		link := row.Find("a.nn-tab-link")
+		headline := map[string]interface{}{
+			"Article Date":  date.Format(time.RFC3339),
+			"Article Title": text(link),
+			"Article URL":   link.AttrOr("href", ""),
+		}
+		if class := news.SourceClass(row.ChildrenFiltered("td").Eq(0).AttrOr("class", "")); class != "" {
+			headline["Source Name"], _ = news.Sources.Lookup(class)
+		}
+		rows = append(rows, headline)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return load([]string{"Article Date", "Article Title", "Article URL", "Source Name"}, rows, "Article Date", "Article Title", "Article URL", "Source Name")
+}
+
+func scrapeMajorNews(doc *goquery.Document) (*dataframe.DataFrame, error) {
+	var rows []map[string]interface{}
+	doc.Find("#major-news").NextAll().Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() < 2 {
+			return
+		}
+		rows = append(rows, map[string]interface{}{"Ticker": text(cells.Eq(0)), "Change": percent(text(cells.Eq(1)))})
+	})
+	return load([]string{"Ticker", "Change"}, rows, "Ticker")
+}
+
+func scrapeEarnings(doc *goquery.Document, fetched time.Time) (*dataframe.DataFrame, error) {
+	results, err := earnings.Scrape(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates list the timing, i.e. "Jan 12/a"
+	var rows []map[string]interface{}
+	for _, result := range results[1:] {
+		date, timing, err := earnings.ParseEarningsDate(result[0], fetched)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, map[string]interface{}{"Date": date.Format("2006-01-02"), "Timing": timing, "Ticker": result[1]})
+	}
+	return load([]string{"Date", "Timing", "Ticker"}, rows, "Date", "Timing", "Ticker")
+}
+
+// scrapeQuotes parses the futures or forex table, whose first header is the provided title
+func scrapeQuotes(doc *goquery.Document, title string) (*dataframe.DataFrame, error) {
+	var rows []map[string]interface{}
+	tablesWithHeader(doc, title).Find("tr").Not(":first-child").Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() < 4 {
+			return
+		}
+		rows = append(rows, map[string]interface{}{
+			"Name":     text(cells.Eq(0)),
+			"Last":     number(text(cells.Eq(1))),
+			"Change":   number(text(cells.Eq(2))),
+			"Change %": percent(text(cells.Eq(3))),
+		})
+	})
+	return load([]string{"Name", "Last", "Change", "Change %"}, rows, "Name")
+}
+
+// scrapeInsiderTrading parses the latest and the top insider trading tables, which share their rows' classes
+func scrapeInsiderTrading(doc *goquery.Document) (latest, top *dataframe.DataFrame, err error) {
+	var latestRows, topRows []map[string]interface{}
+	var current *[]map[string]interface{}
+	doc.Find("table.insider").Find("tr").Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if row.HasClass("t-home-table-top") {
+			switch text(cells.Eq(1)) {
+			case "Latest Insider Trading":
+				current = &latestRows
+			case "Top Insider Trading":
+				current = &topRows
+			default:
+				current = nil
+			}
+			return
+		}
+		switch {
+		case current == &latestRows && cells.Length() >= 8:
+			latestRows = append(latestRows, map[string]interface{}{
+				"Ticker":       text(cells.Eq(0)),
+				"Owner":        text(cells.Eq(1)),
+				"Relationship": text(cells.Eq(2)),
+				"Date":         text(cells.Eq(3)),
+				"Transaction":  text(cells.Eq(4)),
+				"Cost":         number(text(cells.Eq(5))),
+				"#Shares":      number(text(cells.Eq(6))),
+				"Value ($)":    number(text(cells.Eq(7))),
+			})
+		case current == &topRows && cells.Length() >= 5:
+			topRows = append(topRows, map[string]interface{}{
+				"Ticker":      text(cells.Eq(0)),
+				"Owner":       text(cells.Eq(1)),
+				"Date":        text(cells.Eq(2)),
+				"Transaction": text(cells.Eq(3)),
+				"Value ($)":   number(text(cells.Eq(4))),
+			})
+		}
+	})
+
+	if latest, err = load([]string{"Ticker", "Owner", "Relationship", "Date", "Transaction", "Cost", "#Shares", "Value ($)"}, latestRows,
+		"Ticker", "Owner", "Relationship", "Date", "Transaction"); err != nil {
+		return nil, nil, err
+	}
+	if top, err = load([]string{"Ticker", "Owner", "Date", "Transaction", "Value ($)"}, topRows,
+		"Ticker", "Owner", "Date", "Transaction"); err != nil {
+		return nil, nil, err
+	}
+	return latest, top, nil
+}
+
+// tablesWithHeader returns the homepage tables whose first row starts with the provided title
+func tablesWithHeader(doc *goquery.Document, title string) *goquery.Selection {
+	return doc.Find("table.t-home-table").FilterFunction(func(i int, table *goquery.Selection) bool {
+		header := table.Find("tr").First()
+		return header.Closest("table").IsSelection(table) && text(header.ChildrenFiltered("td").First()) == title
+	})
+}
+
+// load returns a DataFrame of the rows, where the provided columns are strings and others are inferred
+func load(headers []string, data []map[string]interface{}, stringColumns ...string) (*dataframe.DataFrame, error) {
+	if len(data) == 0 {
+		columns := make([]series.Series, len(headers))
+		for i, name := range headers {
+			columns[i] = series.New([]string{}, series.String, name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+
+	rows, err := utils.GenerateRows(headers, data)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]series.Type, len(stringColumns))
+	for _, name := range stringColumns {
+		types[name] = series.String
+	}
+	df := dataframe.LoadRecords(rows, dataframe.WithTypes(types))
+	return &df, df.Error()
+}
+
+func text(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// number removes the thousands separators and sign of a number, i.e. "+1,234.5"
+func number(raw string) string {
+	raw = strings.TrimPrefix(strings.ReplaceAll(raw, ",", ""), "+")
+	if _, err := strconv.ParseFloat(raw, 64); err != nil {
+		return "NaN"
+	}
+	return raw
+}
+
+// percent returns a percentage as a fraction, i.e. "-4.23%" is -0.0423
+func percent(raw string) string {
+	value, err := strconv.ParseFloat(number(strings.TrimSuffix(raw, "%")), 64)
+	if err != nil {
+		return "NaN"
+	}
+	return strconv.FormatFloat(value/100, 'f', -1, 64)
+}
@@ -0,0 +1,383 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com/map.ashx"
+)
+
+var (
+	once     sync.Once
+	instance *Client
+)
+
+type Config struct {
+	userAgent string
+	recorder  *recorder.Recorder
+}
+
+type Client struct {
+	*http.Client
+	config Config
+}
+
+func New(config *Config) *Client {
+	once.Do(func() {
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+		}
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		}
+		if config != nil {
+			instance = &Client{Client: client, config: *config}
+		}
+		instance = &Client{
+			Client: client,
+			config: Config{userAgent: uarand.GetRandom()},
+		}
+	})
+
+	return instance
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.config.userAgent)
+	return c.Client.Do(req)
+}
+
+// MapLookup maps the Finviz maps to their t query parameter
+var MapLookup = map[string]string{
+	"sp500": "sec",
+	"full":  "sec_all",
+	"world": "geo",
+	"etf":   "etf",
+}
+
+// LevelLookup names the group levels of each map, from the root's children down to the tickers' parents
+var LevelLookup = map[string][]string{
+	"sp500": {"Sector", "Industry"},
+	"full":  {"Sector", "Industry"},
+	"world": {"Region", "Country"},
+	"etf":   {"Category", "Subcategory"},
+}
+
+// PeriodLookup maps the map performance periods to their st query parameter
+var PeriodLookup = map[string]string{
+	"day":      "d1",
+	"week":     "w1",
+	"month":    "w4",
+	"quarter":  "w13",
+	"halfyear": "w26",
+	"year":     "w52",
+	"ytd":      "ytd",
+}
+
+// Node is a group or ticker of a map. Groups have children, and their market cap and performance aggregate them.
+type Node struct {
+	Name        string
+	Description string
+	// MarketCap is the sum of the children's for groups
+	MarketCap float64
+	// Weight is the fraction of the map's total market cap
+	Weight float64
+	// Performance is a fraction, market cap weighted for groups, and NaN when unknown
+	Performance float64
+	Children    []*Node
+}
+
+// rawNode is the JSON hierarchy embedded in map pages
+type rawNode struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Value       float64    `json:"value"`
+	Children    []*rawNode `json:"children"`
+}
+
+// rawPerformance is the JSON of ticker performances, in percent, embedded in map pages
+type rawPerformance struct {
+	Nodes map[string]float64 `json:"nodes"`
+}
+
+// GetMap returns the tree of a map, with the performance of the period
+func (c *Client) GetMap(name, period string) (*Node, error) {
+	url, err := GenerateURL(name, period)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting map, status code: '%d', body: '%s'", resp.StatusCode, string(body))
+	}
+
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		return nil, err
+	}
+	return Scrape(doc)
+}
+
+// GenerateURL returns the URL of a map and performance period
+func GenerateURL(name, period string) (string, error) {
+	t, exists := MapLookup[name]
+	if !exists {
+		return "", fmt.Errorf("error map '%s' not found, expected one of: %s", name, strings.Join(MapNames(), ", "))
+	}
+	st, exists := PeriodLookup[period]
+	if !exists {
+		return "", fmt.Errorf("error map period '%s' not found, expected one of: %s", period, strings.Join(PeriodNames(), ", "))
+	}
+	return fmt.Sprintf("%s?t=%s&st=%s", APIURL, t, st), nil
+}
+
+// Scrape returns the tree of the JSON hierarchy and performances embedded in a map page's scripts. The layout of
+// that JSON is inferred, not verified against a recorded map page.
+func Scrape(doc *goquery.Document) (*Node, error) {
+	var tree *rawNode
+	var performance *rawPerformance
+	doc.Find("script").Each(func(i int, script *goquery.Selection) {
+		for _, data := range jsonAssignments(script.Text()) {
+			var candidate struct {
+				rawNode
+				rawPerformance
+			}
+			if err := json.Unmarshal(data, &candidate); err != nil {
+				continue
+			}
+			if tree == nil && len(candidate.Children) > 0 {
+				node := candidate.rawNode
+				tree = &node
+			}
+			if performance == nil && candidate.Nodes != nil {
+				perf := candidate.rawPerformance
+				performance = &perf
+			}
+		}
+	})
+	if tree == nil {
+		return nil, fmt.Errorf("error map data not found")
+	}
+	if performance == nil {
+		performance = &rawPerformance{}
+	}
+
+	root := buildNode(tree, performance.Nodes)
+	setWeights(root, root.MarketCap)
+	return root, nil
+}
+
+// jsonAssignments returns the JSON objects assigned in a script, i.e. `var data = {...};`
+func jsonAssignments(script string) []json.RawMessage {
+	var objects []json.RawMessage
+	for i := 0; i < len(script); i++ {
+		if script[i] != '=' {
+			continue
+		}
+		start := i + 1
+		for start < len(script) && (script[start] == ' ' || script[start] == '\n' || script[start] == '\t' || script[start] == '\r') {
+			start++
+		}
+		if start >= len(script) || script[start] != '{' {
+			continue
+		}
+		var object json.RawMessage
+		decoder := json.NewDecoder(strings.NewReader(script[start:]))
+		if err := decoder.Decode(&object); err != nil {
+			continue
+		}
+		objects = append(objects, object)
+		i = start + int(decoder.InputOffset()) - 1
+	}
+	return objects
+}
+
+// buildNode converts the embedded hierarchy, aggregating market caps and performances up the tree
+func buildNode(raw *rawNode, performances map[string]float64) *Node {
+	node := &Node{Name: raw.Name, Description: raw.Description, Performance: math.NaN()}
+	if len(raw.Children) == 0 {
+		node.MarketCap = raw.Value
+		if performance, exists := performances[raw.Name]; exists {
+			node.Performance = performance / 100
+		}
+		return node
+	}
+
+	var weighted, known float64
+	for _, child := range raw.Children {
+		c := buildNode(child, performances)
+		node.Children = append(node.Children, c)
+		node.MarketCap += c.MarketCap
+		if !math.IsNaN(c.Performance) {
+			weighted += c.Performance * c.MarketCap
+			known += c.MarketCap
+		}
+	}
+	if known > 0 {
+		node.Performance = weighted / known
+	}
+	return node
+}
+
+func setWeights(node *Node, total float64) {
+	if total > 0 {
+		node.Weight = node.MarketCap / total
+	}
+	for _, child := range node.Children {
+		setWeights(child, total)
+	}
+}
+
+// DataFrame returns the tickers of a map tree, one row each, with their groups named by the levels, i.e. the
+// LevelLookup of the map
+func DataFrame(root *Node, levels []string) (*dataframe.DataFrame, error) {
+	headers := append(append([]string{}, levels...), "Ticker", "Description", "Market Cap", "Weight", "Performance")
+	var rows []map[string]interface{}
+
+	var walk func(node *Node, groups []string)
+	walk = func(node *Node, groups []string) {
+		if len(node.Children) == 0 {
+			row := map[string]interface{}{
+				"Ticker":      node.Name,
+				"Description": node.Description,
+				"Market Cap":  formatFloat(node.MarketCap),
+				"Weight":      formatFloat(node.Weight),
+				"Performance": formatFloat(node.Performance),
+			}
+			for i, level := range levels {
+				if i < len(groups) {
+					row[level] = groups[i]
+				}
+			}
+			rows = append(rows, row)
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, append(groups[:len(groups):len(groups)], node.Name))
+		}
+	}
+	for _, child := range root.Children {
+		walk(child, nil)
+	}
+
+	return load(headers, rows, append(append([]string{}, levels...), "Ticker", "Description"))
+}
+
+// GroupDataFrame returns the groups of one level of a map tree, where depth 1 is the root's children, i.e. sectors,
+// with their market cap, weight and market cap weighted performance
+func GroupDataFrame(root *Node, depth int) (*dataframe.DataFrame, error) {
+	nodes := []*Node{root}
+	for i := 0; i < depth; i++ {
+		var next []*Node
+		for _, node := range nodes {
+			next = append(next, node.Children...)
+		}
+		nodes = next
+	}
+
+	var rows []map[string]interface{}
+	for _, node := range nodes {
+		if len(node.Children) == 0 {
+			continue
+		}
+		rows = append(rows, map[string]interface{}{
+			"Name":        node.Name,
+			"Market Cap":  formatFloat(node.MarketCap),
+			"Weight":      formatFloat(node.Weight),
+			"Performance": formatFloat(node.Performance),
+		})
+	}
+	return load([]string{"Name", "Market Cap", "Weight", "Performance"}, rows, []string{"Name"})
+}
+
+func load(headers []string, data []map[string]interface{}, stringColumns []string) (*dataframe.DataFrame, error) {
+	if len(data) == 0 {
+		columns := make([]series.Series, len(headers))
+		for i, name := range headers {
+			columns[i] = series.New([]string{}, series.String, name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+
+	rows, err := utils.GenerateRows(headers, data)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]series.Type{"Market Cap": series.Float, "Weight": series.Float, "Performance": series.Float}
+	for _, name := range stringColumns {
+		types[name] = series.String
+	}
+	df := dataframe.LoadRecords(rows, dataframe.WithTypes(types))
+	return &df, df.Error()
+}
+
+func formatFloat(value float64) string {
+	if math.IsNaN(value) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// MapNames returns the names of the maps, sorted
+func MapNames() []string {
+	return keys(MapLookup)
+}
+
+// PeriodNames returns the names of the map performance periods, sorted
+func PeriodNames() []string {
+	return keys(PeriodLookup)
+}
+
+func keys(lookup map[string]string) []string {
+	names := make([]string, 0, len(lookup))
+	for name := range lookup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package maps
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils"
+)
+
+func TestScrape(t *testing.T) {
+	url, err := GenerateURL("sp500", "week")
+	require.Nil(t, err)
+	require.Equal(t, APIURL+"?t=sec&st=w1", url)
+	_, err = GenerateURL("europe", "week")
+	require.NotNil(t, err)
+	require.Equal(t, []string{"etf", "full", "sp500", "world"}, MapNames())
+
+	// No map page is recorded, so the script globals and their JSON shape are assumed, not taken from map.ashx
+	doc, err := utils.GenerateDocument(`<html><head><script type="text/javascript">
+var FinvizMapType = "sec";
+var FinvizMapData = {"name": "Sectors", "children": [
+	{"name": "Technology", "children": [
+		{"name": "Software - Infrastructure", "children": [{"name": "MSFT", "description": "Microsoft Corporation", "value": 3000}]},
+		{"name": "Consumer Electronics", "children": [{"name": "AAPL", "description": "Apple Inc.", "value": 1000}]}
+	]},
+	{"name": "Energy", "children": [
+		{"name": "Oil & Gas Integrated", "children": [{"name": "XOM", "description": "Exxon Mobil Corporation", "value": 1000}, {"name": "CVX", "description": "Chevron Corporation", "value": 0}]}
+	]}
+]};
+var FinvizMapPerf = {"nodes": {"MSFT": -4.0, "AAPL": 2.0, "XOM": 1.5}, "version": 1};
+</script></head><body><div id="map"></div></body></html>`)
+	require.Nil(t, err)
+
+	root, err := Scrape(doc)
+	require.Nil(t, err)
+	require.Equal(t, 5000.0, root.MarketCap)
+	technology := root.Children[0]
+	require.Equal(t, "Technology", technology.Name)
+	require.Equal(t, 0.8, technology.Weight)
+	// Cap weighted: (3000 * -4% + 1000 * 2%) / 4000
+	require.InDelta(t, -0.025, technology.Performance, 1e-12)
+	require.True(t, math.IsNaN(root.Children[1].Children[0].Children[1].Performance))
+	require.InDelta(t, 0.015, root.Children[1].Performance, 1e-12)
+
+	df, err := DataFrame(root, LevelLookup["sp500"])
+	require.Nil(t, err)
+	require.Equal(t, []string{"Sector", "Industry", "Ticker", "Description", "Market Cap", "Weight", "Performance"}, df.Names())
+	require.Equal(t, []string{"MSFT", "AAPL", "XOM", "CVX"}, df.Col("Ticker").Records())
+	require.Equal(t, []string{"Technology", "Technology", "Energy", "Energy"}, df.Col("Sector").Records())
+	require.Equal(t, "Oil & Gas Integrated", df.Col("Industry").Records()[2])
+	require.Equal(t, 0.6, df.Col("Weight").Elem(0).Float())
+
+	sectors, err := GroupDataFrame(root, 1)
+	require.Nil(t, err)
+	require.Equal(t, []string{"Technology", "Energy"}, sectors.Col("Name").Records())
+	require.InDelta(t, -0.025, sectors.Col("Performance").Elem(0).Float(), 1e-12)
+
+	doc, err = utils.GenerateDocument(`<html><head><script>var a = {"b": 1};</script></head></html>`)
+	require.Nil(t, err)
+	_, err = Scrape(doc)
+	require.NotNil(t, err)
+}
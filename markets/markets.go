@@ -0,0 +1,213 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package markets
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com"
+)
+
+var (
+	once     sync.Once
+	instance *Client
+)
+
+type Config struct {
+	userAgent string
+	recorder  *recorder.Recorder
+}
+
+type Client struct {
+	*http.Client
+	config Config
+}
+
+func New(config *Config) *Client {
+	once.Do(func() {
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+		}
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		}
+		if config != nil {
+			instance = &Client{Client: client, config: *config}
+		}
+		instance = &Client{
+			Client: client,
+			config: Config{userAgent: uarand.GetRandom()},
+		}
+	})
+
+	return instance
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.config.userAgent)
+	return c.Client.Do(req)
+}
+
+// MarketLookup maps the markets to their performance page
+var MarketLookup = map[string]string{
+	"futures": "futures_performance.ashx",
+	"forex":   "forex_performance.ashx",
+	"crypto":  "crypto_performance.ashx",
+}
+
+// PerformanceHeaders are the columns of a market performance DataFrame. Instruments are listed by name, and Change is
+// the daily change, as a fraction.
+var PerformanceHeaders = []string{"Instrument", "Last", "Change", "Perf Week", "Perf Month", "Perf Quarter", "Perf Half Y", "Perf Year", "Perf YTD"}
+
+// headerLookup maps the column names of the performance pages to PerformanceHeaders. The names are inferred, not
+// verified against recorded pages, so Scrape reports pages without a match.
+var headerLookup = map[string]string{
+	"name":         "Instrument",
+	"label":        "Instrument",
+	"instrument":   "Instrument",
+	"last":         "Last",
+	"price":        "Last",
+	"change":       "Change",
+	"perf day":     "Change",
+	"perf week":    "Perf Week",
+	"perf month":   "Perf Month",
+	"perf quart":   "Perf Quarter",
+	"perf quarter": "Perf Quarter",
+	"perf half":    "Perf Half Y",
+	"perf half y":  "Perf Half Y",
+	"perf year":    "Perf Year",
+	"perf ytd":     "Perf YTD",
+}
+
+// MarketNames returns the names of the markets, sorted
+func MarketNames() []string {
+	names := make([]string, 0, len(MarketLookup))
+	for name := range MarketLookup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetFutures returns the performance of futures
+func (c *Client) GetFutures() (*dataframe.DataFrame, error) {
+	return c.GetPerformance("futures")
+}
+
+// GetForex returns the performance of currency pairs
+func (c *Client) GetForex() (*dataframe.DataFrame, error) {
+	return c.GetPerformance("forex")
+}
+
+// GetCrypto returns the performance of cryptocurrencies
+func (c *Client) GetCrypto() (*dataframe.DataFrame, error) {
+	return c.GetPerformance("crypto")
+}
+
+// GetPerformance returns the performance of the instruments of one of the MarketLookup markets
+func (c *Client) GetPerformance(market string) (*dataframe.DataFrame, error) {
+	page, exists := MarketLookup[market]
+	if !exists {
+		return nil, fmt.Errorf("error market '%s' not found, expected one of: %s", market, strings.Join(MarketNames(), ", "))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", APIURL, page), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting %s performance, status code: '%d', body: '%s'", market, resp.StatusCode, string(body))
+	}
+
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := Scrape(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	df := dataframe.LoadRecords(results)
+	return utils.CleanFinvizDataFrame(&df), nil
+}
+
+// Scrape returns the rows of a performance page's table with the PerformanceHeaders, headers first. A table without
+// every one of the PerformanceHeaders is an error.
+func Scrape(doc *goquery.Document) ([][]string, error) {
+	header := doc.Find("tr").FilterFunction(func(i int, row *goquery.Selection) bool {
+		return row.ChildrenFiltered("td, th").FilterFunction(func(j int, cell *goquery.Selection) bool {
+			return strings.HasPrefix(strings.ToLower(strings.TrimSpace(cell.Text())), "perf ")
+		}).Length() > 0
+	}).Last()
+	if header.Length() == 0 {
+		return nil, fmt.Errorf("error market performance table not found")
+	}
+
+	var columns []string
+	header.ChildrenFiltered("td, th").Each(func(i int, cell *goquery.Selection) {
+		columns = append(columns, headerLookup[strings.ToLower(strings.TrimSpace(cell.Text()))])
+	})
+	var missing []string
+	for _, name := range PerformanceHeaders {
+		if !utils.Contains(columns, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("error market performance columns not found: %s", strings.Join(missing, ", "))
+	}
+
+	var marketDataSlice []map[string]interface{}
+	header.NextAll().Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() != len(columns) {
+			return
+		}
+		rawMarketData := make(map[string]interface{})
+		cells.Each(func(j int, cell *goquery.Selection) {
+			if columns[j] != "" {
+				rawMarketData[columns[j]] = strings.TrimSpace(cell.Text())
+			}
+		})
+		marketDataSlice = append(marketDataSlice, rawMarketData)
+	})
+
+	return utils.GenerateRows(PerformanceHeaders, marketDataSlice)
+}
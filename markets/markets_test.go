@@ -0,0 +1,51 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package markets
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// TestScrape parses a hand-written performance table, since no futures, forex or crypto page is recorded
+func TestScrape(t *testing.T) {
+	doc, err := utils.GenerateDocument(`<html><body><table width="100%" cellpadding="3" cellspacing="1" border="0" class="table-light">
+<tr><td class="table-top">No.</td><td class="table-top">Name</td><td class="table-top">Price</td><td class="table-top">Perf Day</td><td class="table-top">Perf Week</td><td class="table-top">Perf Month</td><td class="table-top">Perf Quart</td><td class="table-top">Perf Half</td><td class="table-top">Perf Year</td><td class="table-top">Perf YTD</td></tr>
+<tr class="table-light-row-cp"><td>1</td><td><a class="tab-link">Crude Oil</a></td><td>81.71</td><td><span class="is-red">-0.50%</span></td><td>4.31%</td><td>13.02%</td><td>2.77%</td><td>14.20%</td><td>56.18%</td><td>8.64%</td></tr>
+<tr class="table-dark-row-cp"><td>2</td><td><a class="tab-link">Gold</a></td><td>1821.80</td><td>-0.08%</td><td>1.29%</td><td>2.01%</td><td>3.64%</td><td>-0.33%</td><td>-1.60%</td><td>-</td></tr>
+</table></body></html>`)
+	require.Nil(t, err)
+
+	results, err := Scrape(doc)
+	require.Nil(t, err)
+	df := dataframe.LoadRecords(results)
+	df = *utils.CleanFinvizDataFrame(&df)
+	require.Equal(t, PerformanceHeaders, df.Names())
+	require.Equal(t, []string{"Crude Oil", "Gold"}, df.Col("Instrument").Records())
+	require.Equal(t, 81.71, df.Col("Last").Elem(0).Float())
+	require.InDelta(t, -0.005, df.Col("Change").Elem(0).Float(), 1e-12)
+	require.InDelta(t, 0.0364, df.Col("Perf Quarter").Elem(1).Float(), 1e-12)
+	require.True(t, math.IsNaN(df.Col("Perf YTD").Elem(1).Float()))
+
+	doc, err = utils.GenerateDocument("<html><body><table><tr><td>Name</td></tr></table></body></html>")
+	require.Nil(t, err)
+	_, err = Scrape(doc)
+	require.NotNil(t, err)
+
+	// A renamed column is an error rather than a column of NaN
+	doc, err = utils.GenerateDocument("<html><body><table><tr><td>Symbol</td><td>Price</td><td>Perf Day</td><td>Perf Week</td></tr></table></body></html>")
+	require.Nil(t, err)
+	_, err = Scrape(doc)
+	require.EqualError(t, err, "error market performance columns not found: Instrument, Perf Month, Perf Quarter, Perf Half Y, Perf Year, Perf YTD")
+
+	_, err = New(nil).GetPerformance("bonds")
+	require.NotNil(t, err)
+}
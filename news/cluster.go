@@ -0,0 +1,172 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// ClusterOptions controls how near-duplicate headlines are grouped
+type ClusterOptions struct {
+	// Threshold is the minimum Jaccard similarity of normalized title words, from 0 to 1
+	Threshold float64
+	// Window is the maximum time between two articles of the same cluster
+	Window time.Duration
+}
+
+// DefaultClusterOptions groups headlines sharing at least half their words within three hours
+var DefaultClusterOptions = ClusterOptions{
+	Threshold: 0.5,
+	Window:    3 * time.Hour,
+}
+
+// titleStopWords are ignored when comparing titles
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "by": true, "for": true, "from": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"with": true, "after": true, "amid": true, "over": true, "says": true,
+}
+
+// ClusterNews groups near-duplicate articles of a news DataFrame. It appends a Cluster ID column, a Canonical
+// column marking the earliest article of each cluster, and an Alternate Sources column listing the other sources
+// of the cluster on its canonical article.
+func ClusterNews(df *dataframe.DataFrame, options ClusterOptions) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	names := df.Names()
+	for _, name := range []string{"Article Date", "Article Title", "Source Name"} {
+		if !utils.Contains(names, name) {
+			return nil, fmt.Errorf("error news DataFrame has no '%s' column", name)
+		}
+	}
+	if options.Threshold <= 0 || options.Threshold > 1 {
+		return nil, fmt.Errorf("error cluster threshold '%v' must be greater than 0 and at most 1", options.Threshold)
+	}
+
+	titles := df.Col("Article Title").Records()
+	sources := df.Col("Source Name").Records()
+	dates := make([]time.Time, df.Nrow())
+	words := make([]map[string]bool, df.Nrow())
+	for i, raw := range df.Col("Article Date").Records() {
+		date, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing article date '%s': %v", raw, err)
+		}
+		dates[i] = date
+		words[i] = titleWords(titles[i])
+	}
+
+	// Visit articles oldest first, joining each to the first cluster holding a similar article within the window
+	order := make([]int, df.Nrow())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return dates[order[i]].Before(dates[order[j]])
+	})
+
+	var clusters [][]int
+	for _, i := range order {
+		joined := false
+		for c, members := range clusters {
+			for _, j := range members {
+				if dates[i].Sub(dates[j]) <= options.Window && jaccard(words[i], words[j]) >= options.Threshold {
+					clusters[c] = append(clusters[c], i)
+					joined = true
+					break
+				}
+			}
+			if joined {
+				break
+			}
+		}
+		if !joined {
+			clusters = append(clusters, []int{i})
+		}
+	}
+
+	// Number clusters by the position of their first row, so IDs follow the DataFrame order
+	sort.SliceStable(clusters, func(a, b int) bool {
+		return minIndex(clusters[a]) < minIndex(clusters[b])
+	})
+
+	ids := make([]int, df.Nrow())
+	canonical := make([]bool, df.Nrow())
+	alternates := make([]string, df.Nrow())
+	for c, members := range clusters {
+		var others []string
+		for _, i := range members {
+			ids[i] = c + 1
+			if source := sources[members[0]]; sources[i] != source && !utils.Contains(others, sources[i]) {
+				others = append(others, sources[i])
+			}
+		}
+		canonical[members[0]] = true
+		alternates[members[0]] = strings.Join(others, ",")
+	}
+
+	result := df.Mutate(series.New(ids, series.Int, "Cluster ID")).
+		Mutate(series.New(canonical, series.Bool, "Canonical")).
+		Mutate(series.New(alternates, series.String, "Alternate Sources"))
+	return &result, result.Error()
+}
+
+// DedupeNews returns the canonical article of each cluster of near-duplicate articles
+func DedupeNews(df *dataframe.DataFrame, options ClusterOptions) (*dataframe.DataFrame, error) {
+	clustered, err := ClusterNews(df, options)
+	if err != nil {
+		return nil, err
+	}
+	result := clustered.Filter(dataframe.F{Colname: "Canonical", Comparator: series.Eq, Comparando: true})
+	return &result, result.Error()
+}
+
+// titleWords returns the lowercase words of a title, without punctuation or stop words
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$' && r != '%'
+	}) {
+		if !titleStopWords[word] {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// jaccard returns the number of shared words over the number of distinct words
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func minIndex(indexes []int) int {
+	min := indexes[0]
+	for _, i := range indexes {
+		if i < min {
+			min = i
+		}
+	}
+	return min
+}
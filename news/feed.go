@@ -0,0 +1,248 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// FeedInfo describes a news feed
+type FeedInfo struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+// DefaultFeedInfo describes a feed of the Finviz news page
+var DefaultFeedInfo = FeedInfo{
+	Title:       "Finviz News",
+	Link:        APIURL,
+	Description: "The latest news and blog posts from Finviz",
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title      string     `xml:"title"`
+	Link       string     `xml:"link"`
+	GUID       string     `xml:"guid"`
+	PubDate    string     `xml:"pubDate"`
+	Source     *rssSource `xml:"source,omitempty"`
+	Categories []string   `xml:"category"`
+}
+
+type rssSource struct {
+	URL  string `xml:"url,attr"`
+	Name string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// feedItem is a news row shared by the RSS and Atom writers
+type feedItem struct {
+	Date       time.Time
+	Title      string
+	URL        string
+	Source     string
+	SourceURL  string
+	Categories []string
+}
+
+// WriteRSS writes the rows of a news DataFrame as an RSS 2.0 feed
+func WriteRSS(w io.Writer, df *dataframe.DataFrame, info FeedInfo) error {
+	items, err := feedItems(df)
+	if err != nil {
+		return err
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       info.Title,
+			Link:        info.Link,
+			Description: info.Description,
+		},
+	}
+	if len(items) > 0 {
+		feed.Channel.LastBuildDate = items[0].Date.Format(time.RFC1123Z)
+	}
+	for _, item := range items {
+		rss := rssItem{
+			Title:      item.Title,
+			Link:       item.URL,
+			GUID:       item.URL,
+			PubDate:    item.Date.Format(time.RFC1123Z),
+			Categories: item.Categories,
+		}
+		if item.Source != "" {
+			// RSS requires a source URL, so sources without one link to the feed itself
+			rss.Source = &rssSource{URL: item.SourceURL, Name: item.Source}
+			if rss.Source.URL == "" {
+				rss.Source.URL = info.Link
+			}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rss)
+	}
+	return writeXML(w, feed)
+}
+
+// WriteAtom writes the rows of a news DataFrame as an Atom feed
+func WriteAtom(w io.Writer, df *dataframe.DataFrame, info FeedInfo) error {
+	items, err := feedItems(df)
+	if err != nil {
+		return err
+	}
+
+	feed := atomFeed{
+		Title:   info.Title,
+		ID:      info.Link,
+		Link:    atomLink{Href: info.Link},
+		Updated: time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(items) > 0 {
+		feed.Updated = items[0].Date.Format(time.RFC3339)
+	}
+	for _, item := range items {
+		entry := atomEntry{
+			Title:   item.Title,
+			ID:      item.URL,
+			Link:    atomLink{Href: item.URL},
+			Updated: item.Date.Format(time.RFC3339),
+		}
+		if item.Source != "" {
+			entry.Author = &atomAuthor{Name: item.Source, URI: item.SourceURL}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(w, feed)
+}
+
+// feedItems reads the rows of a news DataFrame, newest first. News Type and Tickers become categories.
+func feedItems(df *dataframe.DataFrame) ([]feedItem, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+
+	names := df.Names()
+	for _, name := range []string{"Article Date", "Article Title", "Article URL"} {
+		if !utils.Contains(names, name) {
+			return nil, fmt.Errorf("error news DataFrame has no '%s' column", name)
+		}
+	}
+	column := func(name string) []string {
+		if utils.Contains(names, name) {
+			return df.Col(name).Records()
+		}
+		return make([]string, df.Nrow())
+	}
+
+	dates := column("Article Date")
+	titles := column("Article Title")
+	urls := column("Article URL")
+	sources := column("Source Name")
+	sourceURLs := column("Source URL")
+	types := column("News Type")
+	tickers := column("Tickers")
+
+	items := make([]feedItem, df.Nrow())
+	for i := range items {
+		date, err := time.Parse(time.RFC3339, dates[i])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing article date '%s': %v", dates[i], err)
+		}
+
+		var categories []string
+		if types[i] != "" {
+			categories = append(categories, types[i])
+		}
+		for _, ticker := range strings.Split(tickers[i], ",") {
+			if ticker = strings.TrimSpace(ticker); ticker != "" {
+				categories = append(categories, ticker)
+			}
+		}
+
+		items[i] = feedItem{
+			Date:       date,
+			Title:      titles[i],
+			URL:        urls[i],
+			Source:     sources[i],
+			SourceURL:  sourceURLs[i],
+			Categories: categories,
+		}
+	}
+
+	// The time view interleaves news and blogs, and the source view groups by source
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+	return items, nil
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
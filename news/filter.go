@@ -0,0 +1,186 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// Filter selects news rows. Zero-valued fields select every row.
+type Filter struct {
+	// Sources are matched against Source Name, ignoring case
+	Sources []string
+	// Type is matched against News Type, i.e. "news" or "blog"
+	Type string
+	// Keywords select titles containing any of the keywords, ignoring case
+	Keywords []string
+	// Match selects titles matching the regular expression
+	Match *regexp.Regexp
+	// Tickers select articles tagged with any of the tickers, i.e. a watchlist. See TagMentions.
+	Tickers []string
+	// Since and Until bound the Article Date
+	Since time.Time
+	Until time.Time
+}
+
+// GetFilteredNews returns a DataFrame containing the recent news data selected by the filter
+func (c *Client) GetFilteredNews(view string, filter Filter) (*dataframe.DataFrame, error) {
+	df, err := c.GetNews(view)
+	if err != nil {
+		return nil, err
+	}
+	return FilterNews(df, filter)
+}
+
+// FilterNews returns the rows of a news DataFrame selected by the filter
+func FilterNews(df *dataframe.DataFrame, filter Filter) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+
+	names := df.Names()
+	column := func(name string) ([]string, error) {
+		if !utils.Contains(names, name) {
+			return nil, fmt.Errorf("error news DataFrame has no '%s' column", name)
+		}
+		return df.Col(name).Records(), nil
+	}
+
+	var keep []func(i int) (bool, error)
+	if len(filter.Sources) > 0 {
+		sources, err := column("Source Name")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) (bool, error) {
+			for _, source := range filter.Sources {
+				if strings.EqualFold(sources[i], source) {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+	}
+	if filter.Type != "" {
+		types, err := column("News Type")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) (bool, error) {
+			return strings.EqualFold(types[i], filter.Type), nil
+		})
+	}
+	if len(filter.Keywords) > 0 || filter.Match != nil {
+		titles, err := column("Article Title")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) (bool, error) {
+			if filter.Match != nil && !filter.Match.MatchString(titles[i]) {
+				return false, nil
+			}
+			if len(filter.Keywords) == 0 {
+				return true, nil
+			}
+			title := strings.ToLower(titles[i])
+			for _, keyword := range filter.Keywords {
+				if strings.Contains(title, strings.ToLower(keyword)) {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+	}
+	if len(filter.Tickers) > 0 {
+		tagged, err := column("Tickers")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) (bool, error) {
+			for _, ticker := range strings.Split(tagged[i], ",") {
+				for _, watched := range filter.Tickers {
+					if strings.EqualFold(ticker, watched) {
+						return true, nil
+					}
+				}
+			}
+			return false, nil
+		})
+	}
+	if !filter.Since.IsZero() || !filter.Until.IsZero() {
+		dates, err := column("Article Date")
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, func(i int) (bool, error) {
+			date, err := time.Parse(time.RFC3339, dates[i])
+			if err != nil {
+				return false, fmt.Errorf("error parsing article date '%s': %v", dates[i], err)
+			}
+			return !date.Before(filter.Since) && (filter.Until.IsZero() || !date.After(filter.Until)), nil
+		})
+	}
+
+	var indexes []int
+	for i := 0; i < df.Nrow(); i++ {
+		selected := true
+		for _, k := range keep {
+			ok, err := k(i)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) == 0 {
+		columns := make([]series.Series, len(names))
+		for i, name := range names {
+			columns[i] = series.New([]string{}, df.Col(name).Type(), name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+	result := df.Subset(indexes)
+	return &result, result.Error()
+}
+
+// SourceNames returns the news source names of the Sources registry, sorted
+func SourceNames() []string {
+	return Sources.Names()
+}
+
+// ValidateSources returns the known spelling of each source name, or an error for unknown sources
+func ValidateSources(sources []string) ([]string, error) {
+	names := SourceNames()
+	validated := make([]string, len(sources))
+	for i, source := range sources {
+		for _, name := range names {
+			if strings.EqualFold(strings.TrimSpace(source), name) {
+				validated[i] = name
+				break
+			}
+		}
+		if validated[i] == "" {
+			return nil, fmt.Errorf("error source '%s' not found, expected one of: %s", source, strings.Join(names, ", "))
+		}
+	}
+	return validated, nil
+}
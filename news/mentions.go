@@ -0,0 +1,193 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/universe"
+	"github.com/d3an/finviz/utils"
+)
+
+// DefaultAliases maps common names that differ from the listed company names to tickers
+var DefaultAliases = map[string]string{
+	"Berkshire":      "BRK-B",
+	"Disney":         "DIS",
+	"Facebook":       "META",
+	"Goldman":        "GS",
+	"Google":         "GOOGL",
+	"JPMorgan":       "JPM",
+	"Morgan Stanley": "MS",
+	"Walmart":        "WMT",
+}
+
+var (
+	// cashtagPattern matches $AAPL, and tickerPattern matches (AAPL) and (NASDAQ:AAPL)
+	cashtagPattern = regexp.MustCompile(`\$([A-Za-z]{1,5}(?:[.-][A-Za-z]{1,2})?)\b`)
+	tickerPattern  = regexp.MustCompile(`\((?:[A-Za-z]+:\s*)?([A-Z]{1,5}(?:[.-][A-Z]{1,2})?)\)`)
+
+	// companySuffixes are dropped from the end of company names, i.e. "Apple Inc." is mentioned as "Apple"
+	companySuffixes = map[string]bool{
+		"inc": true, "corp": true, "corporation": true, "co": true, "company": true, "ltd": true, "limited": true,
+		"plc": true, "holdings": true, "holding": true, "group": true, "sa": true, "nv": true, "ag": true,
+		"se": true, "lp": true, "llc": true, "adr": true, "class": true, "a": true, "b": true, "c": true, "com": true,
+	}
+
+	// commonNames are single words too common in headlines to identify a company
+	commonNames = map[string]bool{
+		"american": true, "capital": true, "energy": true, "first": true, "general": true, "global": true,
+		"international": true, "national": true, "united": true, "target": true, "block": true, "gap": true,
+	}
+)
+
+// MentionExtractor finds the tickers mentioned in news titles
+type MentionExtractor struct {
+	tickers  map[string]bool
+	names    map[string]string
+	maxWords int
+}
+
+// NewMentionExtractor creates an extractor of the provided listed tickers, their company names and the aliases of
+// listed tickers
+func NewMentionExtractor(entries []universe.Entry, aliases map[string]string) *MentionExtractor {
+	m := &MentionExtractor{
+		tickers: make(map[string]bool, len(entries)),
+		names:   make(map[string]string, len(entries)+len(aliases)),
+	}
+
+	// The largest company claims a shared name, i.e. GOOGL and GOOG are both "Alphabet Inc."
+	marketCaps := make(map[string]int64, len(entries))
+	for _, e := range entries {
+		ticker := strings.ToUpper(e.Ticker)
+		m.tickers[ticker] = true
+		marketCaps[ticker] = e.MarketCap
+
+		name := companyName(e.Company)
+		if name == "" {
+			continue
+		}
+		if existing, exists := m.names[name]; !exists || e.MarketCap > marketCaps[existing] {
+			m.add(name, ticker)
+		}
+	}
+	// Aliases are only mentions of listed tickers, like company names
+	for alias, ticker := range aliases {
+		if ticker = strings.ToUpper(ticker); m.tickers[ticker] {
+			m.add(strings.Join(nameWords(alias), " "), ticker)
+		}
+	}
+	return m
+}
+
+func (m *MentionExtractor) add(name, ticker string) {
+	m.names[name] = ticker
+	if words := len(strings.Fields(name)); words > m.maxWords {
+		m.maxWords = words
+	}
+}
+
+// Extract returns the sorted tickers mentioned in a title by cashtag, ticker in parentheses, company name or alias
+func (m *MentionExtractor) Extract(title string) []string {
+	var mentions []string
+	mention := func(ticker string) {
+		if m.tickers[ticker] && !utils.Contains(mentions, ticker) {
+			mentions = append(mentions, ticker)
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{cashtagPattern, tickerPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(title, -1) {
+			mention(strings.ReplaceAll(strings.ToUpper(match[1]), ".", "-"))
+		}
+	}
+
+	// Match the longest name starting at each word, so "Bank of America" is not read as "Bank"
+	words := nameWords(title)
+	for i := 0; i < len(words); i++ {
+		for n := m.maxWords; n > 0; n-- {
+			if i+n > len(words) {
+				continue
+			}
+			if ticker, exists := m.names[strings.Join(words[i:i+n], " ")]; exists {
+				mention(ticker)
+				i += n - 1
+				break
+			}
+		}
+	}
+
+	sort.Strings(mentions)
+	return mentions
+}
+
+// TagMentions adds the tickers mentioned in each Article Title to the Tickers column, creating it if needed
+func TagMentions(df *dataframe.DataFrame, m *MentionExtractor) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	names := df.Names()
+	if !utils.Contains(names, "Article Title") {
+		return nil, fmt.Errorf("error news DataFrame has no 'Article Title' column")
+	}
+
+	existing := make([]string, df.Nrow())
+	if utils.Contains(names, "Tickers") {
+		existing = df.Col("Tickers").Records()
+	}
+
+	tickers := make([]string, df.Nrow())
+	for i, title := range df.Col("Article Title").Records() {
+		var tagged []string
+		for _, ticker := range strings.Split(existing[i], ",") {
+			if ticker != "" {
+				tagged = append(tagged, ticker)
+			}
+		}
+		for _, ticker := range m.Extract(title) {
+			if !utils.Contains(tagged, ticker) {
+				tagged = append(tagged, ticker)
+			}
+		}
+		tickers[i] = strings.Join(tagged, ",")
+	}
+
+	result := df.Mutate(series.New(tickers, series.String, "Tickers"))
+	return &result, result.Error()
+}
+
+// companyName returns the words of a company name used to find mentions, without legal suffixes
+func companyName(company string) string {
+	words := nameWords(company)
+	if len(words) > 0 && words[0] == "the" {
+		words = words[1:]
+	}
+	for len(words) > 1 && companySuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	if len(words) == 0 || (len(words) == 1 && (len(words[0]) < 3 || commonNames[words[0]] || titleStopWords[words[0]])) {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+// nameWords splits text into lowercase words, keeping ampersands and inner hyphens, i.e. "AT&T" and "Coca-Cola"
+func nameWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '&' || r == '-')
+	}) {
+		if word = strings.Trim(word, "-"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -10,7 +10,6 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
@@ -102,7 +101,7 @@ func (c *Client) GetNews(view string) (*dataframe.DataFrame, error) {
 		return nil, err
 	}
 
-	results, err := Scrape(view, doc)
+	results, err := Scrape(view, doc, utils.FetchTime(resp))
 	if err != nil {
 		return nil, err
 	}
@@ -122,25 +121,21 @@ func GenerateURL(view string) (string, error) {
 	}
 }
 
-func Scrape(view string, doc *goquery.Document) ([][]string, error) {
+// Scrape scrapes a news view. The fetch time resolves the day and year of article dates.
+func Scrape(view string, doc *goquery.Document, fetched time.Time) ([][]string, error) {
 	switch view {
 	case "time":
-		return ByTimeScrape(doc)
+		return ByTimeScrape(doc, fetched)
 	case "source":
-		return BySourceScrape(doc)
+		return BySourceScrape(doc, fetched)
 	default:
 		return nil, fmt.Errorf("error view '%s' not found", view)
 	}
 }
 
-func ByTimeScrape(doc *goquery.Document) ([][]string, error) {
+func ByTimeScrape(doc *goquery.Document, fetched time.Time) ([][]string, error) {
 	var newsDataSlice []map[string]interface{}
-
-	location, err := time.LoadLocation("EST5EDT")
-	if err != nil {
-		return nil, err
-	}
-	today := time.Now().In(location)
+	var err error
 
 	doc.Find("#news > div").Children().Eq(1).Find("tbody").Eq(0).Children().Eq(1).Children().Each(func(i int, newsColumn *goquery.Selection) {
 		var newsType string
@@ -154,26 +149,21 @@ func ByTimeScrape(doc *goquery.Document) ([][]string, error) {
 
 		if i != 1 {
 			newsColumn.Find("tbody").Eq(0).Children().Each(func(j int, newsItem *goquery.Selection) {
+				if err != nil {
+					return
+				}
 				var rawNewsData = make(map[string]interface{})
 				var date time.Time
 
-				rawDate := newsItem.Children().Eq(1).Text()
-				if date, err = time.Parse("Jan-02-2006 MST", fmt.Sprintf("%s-%d EST", rawDate, today.Year())); err == nil {
-					rawNewsData["Article Date"] = date.Format(time.RFC3339)
-				} else if date, err = time.Parse("January-02-2006 3:04PM MST", fmt.Sprintf("%s-%d-%d %s EST", today.Month(), today.Day(), today.Year(), rawDate)); err == nil {
-					rawNewsData["Article Date"] = date.Format(time.RFC3339)
-				} else {
-					rawNewsData["Article Date"] = rawDate
+				if date, err = utils.ParseNewsDate(newsItem.Children().Eq(1).Text(), fetched); err != nil {
+					return
 				}
+				rawNewsData["Article Date"] = date.Format(time.RFC3339)
 
 				rawNewsData["Article Title"] = newsItem.Children().Eq(2).Children().Eq(0).Text()
 				rawNewsData["Article URL"] = newsItem.Children().Eq(2).Children().Eq(0).AttrOr("href", "")
-				if classes := newsItem.Children().Eq(0).AttrOr("class", ""); classes != "" {
-					if val, exists := SourceAttributeLookup[strings.Split(classes, " ")[1]]; exists {
-						rawNewsData["Source Name"] = val
-					} else {
-						rawNewsData["Source Name"] = "Unknown"
-					}
+				if class := SourceClass(newsItem.Children().Eq(0).AttrOr("class", "")); class != "" {
+					rawNewsData["Source Name"], _ = Sources.Lookup(class)
 				}
 				rawNewsData["News Type"] = newsType
 				newsDataSlice = append(newsDataSlice, rawNewsData)
@@ -181,18 +171,19 @@ func ByTimeScrape(doc *goquery.Document) ([][]string, error) {
 		}
 	})
 
+	if err != nil {
+		return nil, err
+	}
+
 	headers := []string{"Article Date", "Article Title", "Article URL", "Source Name", "News Type"}
 	return utils.GenerateRows(headers, newsDataSlice)
 }
 
-func BySourceScrape(doc *goquery.Document) ([][]string, error) {
+func BySourceScrape(doc *goquery.Document, fetched time.Time) ([][]string, error) {
 	var newsDataSlice []map[string]interface{}
+	var err error
 
-	location, err := time.LoadLocation("EST5EDT")
-	if err != nil {
-		return nil, err
-	}
-	today := time.Now().In(location)
+	Sources.Learn(doc)
 
 	for tableIndex := 2; tableIndex <= 4; tableIndex++ {
 		var newsType string
@@ -215,18 +206,14 @@ func BySourceScrape(doc *goquery.Document) ([][]string, error) {
 								sourceItem := item.Find("tr").Children().Eq(1).Find("a")
 								sourceName = sourceItem.Text()
 								sourceURL = sourceItem.AttrOr("href", "")
-							} else if k > 1 {
+							} else if k > 1 && err == nil {
 								var rawNewsData = make(map[string]interface{})
 								var date time.Time
 
-								rawDate := item.Children().Eq(0).Text()
-								if date, err = time.Parse("Jan-02-2006 MST", fmt.Sprintf("%s-%d EST", rawDate, today.Year())); err == nil {
-									rawNewsData["Article Date"] = date.Format(time.RFC3339)
-								} else if date, err = time.Parse("January-02-2006 3:04PM MST", fmt.Sprintf("%s-%d-%d %s EST", today.Month(), today.Day(), today.Year(), rawDate)); err == nil {
-									rawNewsData["Article Date"] = date.Format(time.RFC3339)
-								} else {
-									rawNewsData["Article Date"] = rawDate
+								if date, err = utils.ParseNewsDate(item.Children().Eq(0).Text(), fetched); err != nil {
+									return
 								}
+								rawNewsData["Article Date"] = date.Format(time.RFC3339)
 
 								rawNewsData["Article Title"] = item.Children().Eq(1).Find("a").Text()
 								rawNewsData["Article URL"] = item.Children().Eq(1).Children().Eq(0).AttrOr("href", "")
@@ -242,11 +229,15 @@ func BySourceScrape(doc *goquery.Document) ([][]string, error) {
 		}
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
 	headers := []string{"Article Date", "Article Title", "Article URL", "Source Name", "Source URL", "News Type"}
 	return utils.GenerateRows(headers, newsDataSlice)
 }
 
-// SourceAttributeLookup provides an interface for identifying news sources based on their CSS attributes
+// SourceAttributeLookup lists the built-in news sources of the Sources registry by their CSS attributes
 var SourceAttributeLookup = map[string]string{
 	"is-1":   "MarketWatch",
 	"is-2":   "WSJ",
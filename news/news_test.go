@@ -6,16 +6,24 @@
 package news
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/corpix/uarand"
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
 	"github.com/stretchr/testify/require"
 
+	"github.com/d3an/finviz/universe"
+	"github.com/d3an/finviz/utils"
 	"github.com/d3an/finviz/utils/test"
 )
 
@@ -102,3 +110,264 @@ func TestGetNews(t *testing.T) {
 		}()
 	}
 }
+
+func TestGetTickerNews(t *testing.T) {
+	func() {
+		r, err := recorder.New("cassettes/ticker_news")
+		require.Nil(t, err)
+		defer func() {
+			err = r.Stop()
+			require.Nil(t, err)
+		}()
+		client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+		articles, err := client.GetTickerArticles([]string{"aapl", "TSLA"})
+		require.Nil(t, err)
+		require.GreaterOrEqual(t, len(articles), 100, "Expected at least 100 articles")
+
+		location, err := time.LoadLocation("America/New_York")
+		require.Nil(t, err)
+		require.Equal(t, time.Date(2021, time.December, 27, 15, 45, 0, 0, location), articles[0].Date)
+		require.Equal(t, "Investor's Business Daily", articles[0].Source)
+		require.Equal(t, []string{"TSLA"}, articles[0].Tickers)
+
+		seen := make(map[string]bool)
+		for i, article := range articles {
+			require.False(t, seen[article.URL], "Duplicate article: %s", article.URL)
+			seen[article.URL] = true
+			if i > 0 {
+				require.False(t, article.Date.After(articles[i-1].Date), "Articles not sorted newest first")
+			}
+		}
+	}()
+}
+
+func TestMergeArticles(t *testing.T) {
+	date := time.Date(2022, time.January, 3, 9, 30, 0, 0, time.UTC)
+	merged := MergeArticles(
+		[]Article{{Date: date, URL: "a", Tickers: []string{"AAPL"}}, {Date: date.Add(time.Hour), URL: "b", Tickers: []string{"AAPL"}}},
+		[]Article{{Date: date, URL: "a", Tickers: []string{"MSFT"}}},
+	)
+
+	require.Equal(t, []Article{
+		{Date: date.Add(time.Hour), URL: "b", Tickers: []string{"AAPL"}},
+		{Date: date, URL: "a", Tickers: []string{"AAPL", "MSFT"}},
+	}, merged)
+
+	rows, err := ArticleRows(merged)
+	require.Nil(t, err)
+	require.Equal(t, []string{"Article Date", "Article Title", "Article URL", "Source Name", "Tickers"}, rows[0])
+	require.Equal(t, "AAPL,MSFT", rows[2][4])
+}
+
+func TestFilterNews(t *testing.T) {
+	func() {
+		r, err := recorder.New("cassettes/time_news_view")
+		require.Nil(t, err)
+		defer func() {
+			err = r.Stop()
+			require.Nil(t, err)
+		}()
+		client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+		sources, err := ValidateSources([]string{"reuters"})
+		require.Nil(t, err)
+		df, err := client.GetFilteredNews("time", Filter{Sources: sources, Type: "news"})
+		require.Nil(t, err)
+		require.Greater(t, df.Nrow(), 0)
+		for _, source := range df.Col("Source Name").Records() {
+			require.Equal(t, "Reuters", source)
+		}
+		for _, newsType := range df.Col("News Type").Records() {
+			require.Equal(t, "news", newsType)
+		}
+	}()
+
+	_, err := ValidateSources([]string{"Unknown Times"})
+	require.NotNil(t, err)
+
+	df := dataframe.LoadRecords([][]string{
+		{"Article Date", "Article Title", "Article URL", "Source Name", "News Type"},
+		{"2022-02-21T18:57:00-05:00", "Fed officials signal March hike", "a", "Reuters", "news"},
+		{"2022-02-21T12:00:00-05:00", "Fed minutes released", "b", "Reuters", "news"},
+		{"2022-02-21T18:30:00-05:00", "Oil jumps on supply fears", "c", "Bloomberg", "news"},
+		{"2022-02-21T18:45:00-05:00", "Federal budget talk", "d", "Zero Hedge", "blog"},
+	})
+	fetched := time.Date(2022, time.February, 21, 19, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	filtered, err := FilterNews(&df, Filter{Match: regexp.MustCompile(`\bFed\b`), Since: fetched.Add(-2 * time.Hour)})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a"}, filtered.Col("Article URL").Records())
+
+	filtered, err = FilterNews(&df, Filter{Keywords: []string{"OIL", "budget"}, Type: "blog"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"d"}, filtered.Col("Article URL").Records())
+
+	filtered, err = FilterNews(&df, Filter{Sources: []string{"WSJ"}})
+	require.Nil(t, err)
+	require.Equal(t, 0, filtered.Nrow())
+	require.Equal(t, df.Names(), filtered.Names())
+}
+
+func TestWriteFeed(t *testing.T) {
+	df := dataframe.LoadRecords([][]string{
+		{"Article Date", "Article Title", "Article URL", "Source Name", "Tickers"},
+		{"2021-12-27T15:20:00-05:00", "Apple & Tesla rally", "https://example.com/a", "Reuters", "AAPL,TSLA"},
+		{"2021-12-27T15:45:00-05:00", "Tesla deliveries", "https://example.com/b", "Investor's Business Daily", "TSLA"},
+	})
+
+	var rss bytes.Buffer
+	require.Nil(t, WriteRSS(&rss, &df, DefaultFeedInfo))
+
+	var feed struct {
+		Channel struct {
+			Items []struct {
+				Title      string   `xml:"title"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Source     string   `xml:"source"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.Nil(t, xml.Unmarshal(rss.Bytes(), &feed))
+	require.Equal(t, 2, len(feed.Channel.Items))
+	require.Equal(t, "https://example.com/b", feed.Channel.Items[0].GUID)
+	require.Equal(t, "Mon, 27 Dec 2021 15:45:00 -0500", feed.Channel.Items[0].PubDate)
+	require.Equal(t, "Apple & Tesla rally", feed.Channel.Items[1].Title)
+	require.Equal(t, "Reuters", feed.Channel.Items[1].Source)
+	require.Equal(t, []string{"AAPL", "TSLA"}, feed.Channel.Items[1].Categories)
+
+	var atom bytes.Buffer
+	require.Nil(t, WriteAtom(&atom, &df, DefaultFeedInfo))
+
+	var atomFeed struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID     string `xml:"id"`
+			Author struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+		} `xml:"entry"`
+	}
+	require.Nil(t, xml.Unmarshal(atom.Bytes(), &atomFeed))
+	require.Equal(t, "2021-12-27T15:45:00-05:00", atomFeed.Updated)
+	require.Equal(t, 2, len(atomFeed.Entries))
+	require.Equal(t, "Investor's Business Daily", atomFeed.Entries[0].Author.Name)
+
+	missing := dataframe.LoadRecords([][]string{{"Article Title"}, {"a"}})
+	require.NotNil(t, WriteRSS(&rss, &missing, DefaultFeedInfo))
+}
+
+func TestSourceRegistry(t *testing.T) {
+	doc, err := utils.GenerateDocument(`<table><tr>
+		<td width="20" class="news_source_icon is-left is-3"></td>
+		<td><a href="http://www.reuters.com/" class="nn-title-link">Reuters</a></td>
+	</tr><tr>
+		<td width="20" class="news_source_icon is-left is-200"></td>
+		<td><a href="https://example.com/" class="nn-title-link">Example Wire</a></td>
+	</tr></table>`)
+	require.Nil(t, err)
+
+	registry := NewSourceRegistry(map[string]string{"is-3": "Reuters"})
+	name, ok := registry.Lookup("is-200")
+	require.False(t, ok)
+	require.Equal(t, UnknownSource, name)
+	require.Equal(t, map[string]int{"is-200": 1}, registry.Unknown())
+
+	require.Equal(t, []string{"is-200"}, registry.Learn(doc))
+	name, ok = registry.Lookup("is-200")
+	require.True(t, ok)
+	require.Equal(t, "Example Wire", name)
+	require.Empty(t, registry.Unknown())
+	require.Equal(t, []string{"Example Wire", "Reuters"}, registry.Names())
+
+	path := filepath.Join(t.TempDir(), "sources.json")
+	require.Nil(t, registry.SaveFile(path))
+	loaded := NewSourceRegistry(nil)
+	require.Nil(t, loaded.LoadFile(path))
+	require.Equal(t, registry.Mappings(), loaded.Mappings())
+	require.Nil(t, loaded.LoadFile(filepath.Join(t.TempDir(), "missing.json")))
+
+	require.Nil(t, os.WriteFile(path, []byte(`{"bloomberg": "Bloomberg"}`), 0o644))
+	require.NotNil(t, loaded.LoadFile(path))
+
+	// Every source class of the recorded time view is built in
+	r, err := recorder.New("cassettes/time_news_view")
+	require.Nil(t, err)
+	defer func() {
+		err = r.Stop()
+		require.Nil(t, err)
+	}()
+	client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+	df, err := client.GetNews("time")
+	require.Nil(t, err)
+	require.NotContains(t, df.Col("Source Name").Records(), UnknownSource)
+}
+
+func TestClusterNews(t *testing.T) {
+	df := dataframe.LoadRecords([][]string{
+		{"Article Date", "Article Title", "Article URL", "Source Name", "News Type"},
+		{"2022-02-21T18:30:00-05:00", "Stocks Tumble as Russia Orders Troops Into Ukraine", "a", "Bloomberg", "news"},
+		{"2022-02-21T18:10:00-05:00", "Oil jumps on supply fears", "b", "Reuters", "news"},
+		{"2022-02-21T18:05:00-05:00", "Stocks tumble after Russia orders troops into Ukraine", "c", "Reuters", "news"},
+		{"2022-02-21T17:50:00-05:00", "Russia orders troops into Ukraine; stocks tumble", "d", "CNBC", "news"},
+		{"2022-02-21T09:00:00-05:00", "Stocks tumble as Russia orders troops into Ukraine", "e", "WSJ", "news"},
+	})
+
+	clustered, err := ClusterNews(&df, DefaultClusterOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"1", "2", "1", "1", "3"}, clustered.Col("Cluster ID").Records())
+	require.Equal(t, []string{"false", "true", "false", "true", "true"}, clustered.Col("Canonical").Records())
+	require.Equal(t, "Reuters,Bloomberg", clustered.Col("Alternate Sources").Records()[3])
+
+	deduped, err := DedupeNews(&df, DefaultClusterOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"b", "d", "e"}, deduped.Col("Article URL").Records())
+
+	_, err = ClusterNews(&df, ClusterOptions{Threshold: 0, Window: time.Hour})
+	require.NotNil(t, err)
+}
+
+func TestTagMentions(t *testing.T) {
+	extractor := NewMentionExtractor([]universe.Entry{
+		{Ticker: "AAPL", Company: "Apple Inc.", MarketCap: 2900000000000},
+		{Ticker: "GOOG", Company: "Alphabet Inc.", MarketCap: 1800000000000},
+		{Ticker: "GOOGL", Company: "Alphabet Inc.", MarketCap: 1900000000000},
+		{Ticker: "BAC", Company: "Bank of America Corp.", MarketCap: 380000000000},
+		{Ticker: "TGT", Company: "Target Corp.", MarketCap: 110000000000},
+		{Ticker: "TSLA", Company: "Tesla, Inc.", MarketCap: 900000000000},
+		{Ticker: "AMZN", Company: "Amazon.com, Inc.", MarketCap: 1600000000000},
+		{Ticker: "META", Company: "Meta Platforms, Inc.", MarketCap: 900000000000},
+	}, DefaultAliases)
+
+	values := []struct {
+		title    string
+		expected []string
+	}{
+		{"Apple's iPhone sales beat estimates", []string{"AAPL"}},
+		{"Alphabet and Amazon.com lead tech rally", []string{"AMZN", "GOOGL"}},
+		{"Bank of America raises price target on $TSLA", []string{"BAC", "TSLA"}},
+		{"Google unveils new chip (NASDAQ:AAPL) $XYZ", []string{"AAPL", "GOOGL"}},
+		{"Stocks tumble as Russia orders troops into Ukraine", nil},
+		// Disney is an alias of DIS, which is not listed
+		{"Facebook and Disney shares slide", []string{"META"}},
+	}
+	for _, v := range values {
+		require.Equal(t, v.expected, extractor.Extract(v.title), v.title)
+	}
+
+	df := dataframe.LoadRecords([][]string{
+		{"Article Title", "Article URL", "Tickers"},
+		{"Tesla deliveries top estimates", "a", "TSLA"},
+		{"Apple nears $3 trillion value", "b", ""},
+		{"Oil jumps on supply fears", "c", ""},
+	})
+	tagged, err := TagMentions(&df, extractor)
+	require.Nil(t, err)
+	require.Equal(t, []string{"TSLA", "AAPL", ""}, tagged.Col("Tickers").Records())
+
+	watched, err := FilterNews(tagged, Filter{Tickers: []string{"aapl", "MSFT"}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"b"}, watched.Col("Article URL").Records())
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// UnknownSource is the source name of articles whose source class is not registered
+const UnknownSource = "Unknown"
+
+// SourceRegistry maps the is-N CSS classes of news source icons to source names. It is safe for concurrent use.
+type SourceRegistry struct {
+	mu      sync.RWMutex
+	names   map[string]string
+	unknown map[string]int
+}
+
+// Sources is the registry used when scraping news. It starts with SourceAttributeLookup and learns from the source view.
+var Sources = NewSourceRegistry(SourceAttributeLookup)
+
+// NewSourceRegistry returns a registry of the provided class to source name mappings
+func NewSourceRegistry(names map[string]string) *SourceRegistry {
+	r := &SourceRegistry{
+		names:   make(map[string]string, len(names)),
+		unknown: make(map[string]int),
+	}
+	for class, name := range names {
+		r.names[class] = name
+	}
+	return r
+}
+
+// Lookup returns the source name of a class. Unregistered classes are recorded and reported by Unknown.
+func (r *SourceRegistry) Lookup(class string) (string, bool) {
+	r.mu.RLock()
+	name, exists := r.names[class]
+	r.mu.RUnlock()
+	if exists {
+		return name, true
+	}
+
+	r.mu.Lock()
+	r.unknown[class]++
+	r.mu.Unlock()
+	return UnknownSource, false
+}
+
+// Register maps a class to a source name, replacing any existing mapping
+func (r *SourceRegistry) Register(class, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names[class] = name
+	delete(r.unknown, class)
+}
+
+// Learn registers the mappings listed on a source view page, returning the classes that were not already registered
+func (r *SourceRegistry) Learn(doc *goquery.Document) []string {
+	var learned []string
+	doc.Find("td.news_source_icon").Each(func(i int, icon *goquery.Selection) {
+		class := SourceClass(icon.AttrOr("class", ""))
+		name := strings.TrimSpace(icon.Next().Find("a.nn-title-link").Text())
+		if class == "" || name == "" {
+			return
+		}
+
+		r.mu.RLock()
+		existing, exists := r.names[class]
+		r.mu.RUnlock()
+		if !exists || existing != name {
+			r.Register(class, name)
+			learned = append(learned, class)
+		}
+	})
+	return learned
+}
+
+// Unknown returns the unregistered classes seen by Lookup, with the number of lookups of each
+func (r *SourceRegistry) Unknown() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	unknown := make(map[string]int, len(r.unknown))
+	for class, count := range r.unknown {
+		unknown[class] = count
+	}
+	return unknown
+}
+
+// Names returns the registered source names, sorted
+func (r *SourceRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var names []string
+	for _, name := range r.names {
+		if !utils.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mappings returns a copy of the registered class to source name mappings
+func (r *SourceRegistry) Mappings() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make(map[string]string, len(r.names))
+	for class, name := range r.names {
+		names[class] = name
+	}
+	return names
+}
+
+// DefaultSourcesPath returns the default location of the source registry file
+func DefaultSourcesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "finviz", "news_sources.json"), nil
+}
+
+// LoadFile registers the mappings of a JSON file of the form {"is-7": "Bloomberg"}. A missing file is not an error.
+func (r *SourceRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var names map[string]string
+	if err = json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("error parsing news source file '%s': %v", path, err)
+	}
+	for class, name := range names {
+		if SourceClass(class) != class {
+			return fmt.Errorf("error news source class '%s' in '%s' is not of the form 'is-N'", class, path)
+		}
+		r.Register(class, name)
+	}
+	return nil
+}
+
+// SaveFile writes the registered mappings to a JSON file
+func (r *SourceRegistry) SaveFile(path string) error {
+	data, err := json.MarshalIndent(r.Mappings(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SourceClass returns the is-N class of a source icon's class attribute, i.e. "news_source_icon is-left is-7"
+func SourceClass(classes string) string {
+	for _, class := range strings.Fields(classes) {
+		if number := strings.TrimPrefix(class, "is-"); number != class && number != "" && strings.Trim(number, "0123456789") == "" {
+			return class
+		}
+	}
+	return ""
+}
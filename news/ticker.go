@@ -0,0 +1,186 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package news
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/utils"
+)
+
+// Article is a single news item listed on one or more quote pages
+type Article struct {
+	Date    time.Time
+	Title   string
+	URL     string
+	Source  string
+	Tickers []string
+}
+
+// GetTickerNews returns a DataFrame containing the news of the provided tickers, newest first. Only the news table of
+// each quote page is scraped, which lists about the latest 100 articles; older history is not paged through.
+func (c *Client) GetTickerNews(tickers []string) (*dataframe.DataFrame, error) {
+	articles, err := c.GetTickerArticles(tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := ArticleRows(articles)
+	if err != nil {
+		return nil, err
+	}
+
+	df := dataframe.LoadRecords(results)
+	return &df, nil
+}
+
+// GetTickerArticles returns the quote page news of the provided tickers, de-duplicated by URL and sorted newest first
+func (c *Client) GetTickerArticles(tickers []string) ([]Article, error) {
+	var articles [][]Article
+	for _, ticker := range tickers {
+		url, err := quote.GenerateURL(ticker)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error getting news for ticker '%s', status code: '%d'", ticker, resp.StatusCode)
+		}
+
+		doc, err := utils.GenerateDocument(body)
+		if err != nil {
+			return nil, err
+		}
+
+		results, err := TickerNewsScrape(ticker, doc, utils.FetchTime(resp))
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, results)
+	}
+
+	return MergeArticles(articles...), nil
+}
+
+// TickerNewsScrape scrapes the news table of a quote page. The fetch time resolves "Today" rows.
+func TickerNewsScrape(ticker string, doc *goquery.Document, fetched time.Time) ([]Article, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+
+	var articles []Article
+	var day string
+	var err error
+	doc.Find("#news-table tr").EachWithBreak(func(i int, row *goquery.Selection) bool {
+		link := row.Find("td").Eq(1).Find("a").First()
+		if len(link.Nodes) == 0 {
+			return true
+		}
+
+		// Rows either carry a full date and time, or just the time of the previous row's date
+		fields := strings.Fields(row.Find("td").Eq(0).Text())
+		switch len(fields) {
+		case 1:
+			if day == "" {
+				err = fmt.Errorf("error news row '%d' for ticker '%s' has no date", i, ticker)
+				return false
+			}
+		case 2:
+			day = fields[0]
+		default:
+			err = fmt.Errorf("error unexpected news date '%s' for ticker '%s'", row.Find("td").Eq(0).Text(), ticker)
+			return false
+		}
+
+		var date time.Time
+		if date, err = utils.ParseNewsDate(fmt.Sprintf("%s %s", day, fields[len(fields)-1]), fetched); err != nil {
+			return false
+		}
+
+		source := strings.TrimSpace(row.Find("td").Eq(1).Find("span").First().Text())
+		articles = append(articles, Article{
+			Date:    date,
+			Title:   strings.TrimSpace(link.Text()),
+			URL:     link.AttrOr("href", ""),
+			Source:  strings.TrimSuffix(strings.TrimPrefix(source, "("), ")"),
+			Tickers: []string{ticker},
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// MergeArticles de-duplicates articles by URL, combining their tickers, and sorts them newest first
+func MergeArticles(groups ...[]Article) []Article {
+	var merged []Article
+	index := make(map[string]int)
+
+	for _, group := range groups {
+		for _, article := range group {
+			i, exists := index[article.URL]
+			if !exists {
+				article.Tickers = append([]string(nil), article.Tickers...)
+				index[article.URL] = len(merged)
+				merged = append(merged, article)
+				continue
+			}
+			for _, ticker := range article.Tickers {
+				if !utils.Contains(merged[i].Tickers, ticker) {
+					merged[i].Tickers = append(merged[i].Tickers, ticker)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Date.After(merged[j].Date)
+	})
+	return merged
+}
+
+// ArticleRows generates DataFrame records from articles
+func ArticleRows(articles []Article) ([][]string, error) {
+	newsDataSlice := make([]map[string]interface{}, len(articles))
+	for i, article := range articles {
+		newsDataSlice[i] = map[string]interface{}{
+			"Article Date":  article.Date.Format(time.RFC3339),
+			"Article Title": article.Title,
+			"Article URL":   article.URL,
+			"Source Name":   article.Source,
+			"Tickers":       strings.Join(article.Tickers, ","),
+		}
+	}
+
+	headers := []string{"Article Date", "Article Title", "Article URL", "Source Name", "Tickers"}
+	return utils.GenerateRows(headers, newsDataSlice)
+}
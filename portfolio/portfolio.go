@@ -0,0 +1,376 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package portfolio
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/corpix/uarand"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+
+	"github.com/d3an/finviz/quote"
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com"
+	// SessionCookie is the cookie of a signed in Finviz session
+	SessionCookie = ".ASPXAUTH"
+)
+
+// Pages of the Finviz portfolio app, relative to the base URL. Only portfolio.ashx is public; the sign in and edit
+// pages, their form fields and the select#pid menu are inferred from the site and unverified, since no signed in
+// session is recorded.
+const (
+	loginPage     = "/login_submit.ashx"
+	portfolioPage = "/portfolio.ashx"
+	editPage      = "/portfolio_edit.ashx"
+)
+
+var (
+	once     sync.Once
+	instance *Client
+)
+
+type Config struct {
+	userAgent string
+	recorder  *recorder.Recorder
+	// baseURL replaces APIURL, i.e. for a test server
+	baseURL string
+}
+
+type Client struct {
+	*http.Client
+	config Config
+}
+
+func New(config *Config) *Client {
+	once.Do(func() {
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+		}
+		// Sessions are kept in the cookie jar
+		jar, _ := cookiejar.New(nil)
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+			Jar:       jar,
+		}
+		if config != nil {
+			instance = &Client{Client: client, config: *config}
+		} else {
+			instance = &Client{
+				Client: client,
+				config: Config{userAgent: uarand.GetRandom()},
+			}
+		}
+	})
+
+	return instance
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.config.userAgent)
+	return c.Client.Do(req)
+}
+
+// Portfolio is a Finviz portfolio of the signed in user
+type Portfolio struct {
+	ID   int
+	Name string
+}
+
+// Position is a transaction adding shares of a ticker to a portfolio
+type Position struct {
+	Ticker string
+	Shares float64
+	// Price is the cost per share
+	Price float64
+	// Date defaults to today
+	Date time.Time
+}
+
+// HoldingHeaders are the columns of a holdings DataFrame. Gain % is a fraction.
+var HoldingHeaders = []string{"Ticker", "Shares", "Cost", "Value", "Gain", "Gain %"}
+
+// holdingLookup maps the column names of portfolio pages to HoldingHeaders
+var holdingLookup = map[string]string{
+	"ticker":       "Ticker",
+	"shares":       "Shares",
+	"cost":         "Cost",
+	"cost $":       "Cost",
+	"value":        "Value",
+	"market value": "Value",
+	"value $":      "Value",
+	"gain":         "Gain",
+	"gain $":       "Gain",
+	"gain%":        "Gain %",
+	"gain %":       "Gain %",
+}
+
+// Login signs in with a Finviz account, keeping the session for later requests
+func (c *Client) Login(email, password string) error {
+	form := url.Values{"email": {email}, "password": {password}, "remember": {"true"}}
+	req, err := http.NewRequest(http.MethodPost, c.url(loginPage), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, err = c.send(req); err != nil {
+		return err
+	}
+	if !c.Authenticated() {
+		return utils.NotAuthenticatedError("error signing in to Finviz, check the email and password")
+	}
+	return nil
+}
+
+// SetSession uses the value of a signed in browser's SessionCookie instead of signing in
+func (c *Client) SetSession(value string) error {
+	base, err := url.Parse(c.url("/"))
+	if err != nil {
+		return err
+	}
+	if c.Jar == nil {
+		if c.Jar, err = cookiejar.New(nil); err != nil {
+			return err
+		}
+	}
+	c.Jar.SetCookies(base, []*http.Cookie{{Name: SessionCookie, Value: value, Path: "/"}})
+	return nil
+}
+
+// Authenticated reports whether the client has a session cookie
+func (c *Client) Authenticated() bool {
+	base, err := url.Parse(c.url("/"))
+	if err != nil || c.Jar == nil {
+		return false
+	}
+	for _, cookie := range c.Jar.Cookies(base) {
+		if cookie.Name == SessionCookie && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPortfolios returns the portfolios of the signed in user
+func (c *Client) GetPortfolios() ([]Portfolio, error) {
+	doc, err := c.get(c.url(portfolioPage))
+	if err != nil {
+		return nil, err
+	}
+
+	var portfolios []Portfolio
+	doc.Find("select#pid option").Each(func(i int, option *goquery.Selection) {
+		id, err := strconv.Atoi(option.AttrOr("value", ""))
+		if err != nil || id <= 0 {
+			return
+		}
+		portfolios = append(portfolios, Portfolio{ID: id, Name: strings.TrimSpace(option.Text())})
+	})
+	return portfolios, nil
+}
+
+// GetHoldings returns the holdings of a portfolio
+func (c *Client) GetHoldings(pid int) (*dataframe.DataFrame, error) {
+	doc, err := c.get(fmt.Sprintf("%s?v=1&pid=%d", c.url(portfolioPage), pid))
+	if err != nil {
+		return nil, err
+	}
+	return ScrapeHoldings(doc)
+}
+
+// AddPosition adds a transaction to a portfolio
+func (c *Client) AddPosition(pid int, p Position) error {
+	ticker, err := quote.NormalizeTicker(p.Ticker)
+	if err != nil {
+		return err
+	}
+	if p.Shares <= 0 {
+		return fmt.Errorf("error position of '%s' has no shares", ticker)
+	}
+	if p.Price <= 0 {
+		return fmt.Errorf("error position of '%s' has no price", ticker)
+	}
+	if p.Date.IsZero() {
+		p.Date = time.Now()
+	}
+	return c.edit(pid, url.Values{
+		"action": {"add"},
+		"ticker": {ticker},
+		"shares": {strconv.FormatFloat(p.Shares, 'f', -1, 64)},
+		"price":  {strconv.FormatFloat(p.Price, 'f', -1, 64)},
+		"date":   {p.Date.In(utils.Eastern).Format("01/02/2006")},
+	})
+}
+
+// RemovePosition removes every transaction of a ticker from a portfolio
+func (c *Client) RemovePosition(pid int, ticker string) error {
+	ticker, err := quote.NormalizeTicker(ticker)
+	if err != nil {
+		return err
+	}
+	return c.edit(pid, url.Values{"action": {"delete"}, "ticker": {ticker}})
+}
+
+func (c *Client) edit(pid int, form url.Values) error {
+	if !c.Authenticated() {
+		return utils.NotAuthenticatedError("error editing portfolios requires signing in to Finviz")
+	}
+	form.Set("pid", strconv.Itoa(pid))
+	req, err := http.NewRequest(http.MethodPost, c.url(editPage), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = c.send(req)
+	return err
+}
+
+// ScrapeHoldings returns the holdings of a portfolio page, without its total row
+func ScrapeHoldings(doc *goquery.Document) (*dataframe.DataFrame, error) {
+	header := doc.Find("tr").FilterFunction(func(i int, row *goquery.Selection) bool {
+		var names []string
+		row.ChildrenFiltered("td, th").Each(func(j int, cell *goquery.Selection) {
+			names = append(names, strings.ToLower(strings.TrimSpace(cell.Text())))
+		})
+		return utils.Contains(names, "ticker") && utils.Contains(names, "shares")
+	}).Last()
+	if header.Length() == 0 {
+		return nil, fmt.Errorf("error portfolio holdings table not found")
+	}
+
+	var columns []string
+	header.ChildrenFiltered("td, th").Each(func(i int, cell *goquery.Selection) {
+		columns = append(columns, holdingLookup[strings.ToLower(strings.TrimSpace(cell.Text()))])
+	})
+
+	var holdingDataSlice []map[string]interface{}
+	header.NextAll().Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td")
+		if cells.Length() != len(columns) {
+			return
+		}
+		rawHoldingData := make(map[string]interface{})
+		cells.Each(func(j int, cell *goquery.Selection) {
+			value := strings.TrimSpace(cell.Text())
+			switch columns[j] {
+			case "":
+				return
+			case "Ticker":
+				rawHoldingData[columns[j]] = value
+			case "Gain %":
+				rawHoldingData[columns[j]] = percent(value)
+			default:
+				rawHoldingData[columns[j]] = number(value)
+			}
+		})
+		if ticker, _ := rawHoldingData["Ticker"].(string); ticker == "" || strings.EqualFold(ticker, "total") {
+			return
+		}
+		holdingDataSlice = append(holdingDataSlice, rawHoldingData)
+	})
+
+	if len(holdingDataSlice) == 0 {
+		columns := make([]series.Series, len(HoldingHeaders))
+		for i, name := range HoldingHeaders {
+			columns[i] = series.New([]string{}, series.String, name)
+		}
+		empty := dataframe.New(columns...)
+		return &empty, empty.Error()
+	}
+	rows, err := utils.GenerateRows(HoldingHeaders, holdingDataSlice)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]series.Type{"Ticker": series.String}
+	for _, name := range HoldingHeaders[1:] {
+		types[name] = series.Float
+	}
+	df := dataframe.LoadRecords(rows, dataframe.WithTypes(types))
+	return &df, df.Error()
+}
+
+// get requests a page of the signed in user
+func (c *Client) get(pageURL string) (*goquery.Document, error) {
+	if !c.Authenticated() {
+		return nil, utils.NotAuthenticatedError("error portfolios require signing in to Finviz")
+	}
+	req, err := http.NewRequest(http.MethodGet, pageURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GenerateDocument(body)
+}
+
+// send performs a request, reporting expired sessions, which are redirected to the login page
+func (c *Client) send(req *http.Request) ([]byte, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error requesting '%s', status code: '%d', body: '%s'", req.URL.Path, resp.StatusCode, string(body))
+	}
+	if strings.HasPrefix(resp.Request.URL.Path, "/login") {
+		return nil, utils.NotAuthenticatedError("error Finviz session expired, sign in again")
+	}
+	return body, nil
+}
+
+func (c *Client) url(page string) string {
+	base := c.config.baseURL
+	if base == "" {
+		base = APIURL
+	}
+	return strings.TrimSuffix(base, "/") + page
+}
+
+// number removes the thousands separators and currency of a number, i.e. "$1,234.50"
+func number(raw string) string {
+	raw = strings.NewReplacer(",", "", "$", "", "+", "").Replace(raw)
+	if _, err := strconv.ParseFloat(raw, 64); err != nil {
+		return "NaN"
+	}
+	return raw
+}
+
+// percent returns a percentage as a fraction, i.e. "-4.23%" is -0.0423
+func percent(raw string) string {
+	value, err := strconv.ParseFloat(number(strings.TrimSuffix(raw, "%")), 64)
+	if err != nil {
+		return "NaN"
+	}
+	return strconv.FormatFloat(value/100, 'f', -1, 64)
+}
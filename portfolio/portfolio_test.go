@@ -0,0 +1,212 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package portfolio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// fakeFinviz is an in-memory Finviz portfolio app with a single account. It mirrors the unverified pages and form
+// fields of the client, so it tests the client's handling of them rather than Finviz itself.
+type fakeFinviz struct {
+	mu         sync.Mutex
+	portfolios map[int]string
+	// holdings maps portfolio ids to their shares and cost of each ticker
+	holdings map[int]map[string][2]float64
+	edits    []string
+}
+
+const (
+	fakeEmail    = "trader@example.com"
+	fakePassword = "hunter2"
+	fakeSession  = "signed-in"
+)
+
+func newFakeFinviz() *fakeFinviz {
+	return &fakeFinviz{
+		portfolios: map[int]string{101: "Growth", 102: "Dividends"},
+		holdings: map[int]map[string][2]float64{
+			101: {"AAPL": {10, 1500}, "MSFT": {5, 1250}},
+			102: {},
+		},
+	}
+}
+
+func (f *fakeFinviz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case loginPage:
+		if r.FormValue("email") != fakeEmail || r.FormValue("password") != fakePassword {
+			http.Redirect(w, r, "/login.ashx?error=1", http.StatusFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: fakeSession, Path: "/"})
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	case "/login.ashx":
+		fmt.Fprint(w, `<html><body><form action="/login_submit.ashx"></form></body></html>`)
+		return
+	case "/":
+		fmt.Fprint(w, `<html><body>Home</body></html>`)
+		return
+	}
+
+	if cookie, err := r.Cookie(SessionCookie); err != nil || cookie.Value != fakeSession {
+		http.Redirect(w, r, "/login.ashx", http.StatusFound)
+		return
+	}
+
+	switch r.URL.Path {
+	case portfolioPage:
+		pid, _ := strconv.Atoi(r.URL.Query().Get("pid"))
+		if pid == 0 {
+			pid = 101
+		}
+		fmt.Fprint(w, f.page(pid))
+	case editPage:
+		pid, _ := strconv.Atoi(r.FormValue("pid"))
+		holdings, exists := f.holdings[pid]
+		if !exists {
+			http.Error(w, "portfolio not found", http.StatusNotFound)
+			return
+		}
+		ticker := r.FormValue("ticker")
+		f.edits = append(f.edits, fmt.Sprintf("%s %d %s %s %s %s", r.FormValue("action"), pid, ticker, r.FormValue("shares"), r.FormValue("price"), r.FormValue("date")))
+		switch r.FormValue("action") {
+		case "add":
+			shares, _ := strconv.ParseFloat(r.FormValue("shares"), 64)
+			price, _ := strconv.ParseFloat(r.FormValue("price"), 64)
+			h := holdings[ticker]
+			holdings[ticker] = [2]float64{h[0] + shares, h[1] + shares*price}
+		case "delete":
+			delete(holdings, ticker)
+		}
+		http.Redirect(w, r, fmt.Sprintf("%s?v=1&pid=%d", portfolioPage, pid), http.StatusFound)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// page renders a portfolio page, valuing every share at $200
+func (f *fakeFinviz) page(pid int) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><select id="pid">`)
+	ids := make([]int, 0, len(f.portfolios))
+	for id := range f.portfolios {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		fmt.Fprintf(&b, `<option value="%d">%s</option>`, id, f.portfolios[id])
+	}
+	b.WriteString(`<option value="-1">Create New Portfolio</option></select>`)
+
+	b.WriteString(`<table><tr><td>Ticker</td><td>Company</td><td>Shares</td><td>Cost $</td><td>Value $</td><td>Gain $</td><td>Gain %</td></tr>`)
+	tickers := make([]string, 0, len(f.holdings[pid]))
+	for ticker := range f.holdings[pid] {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	var totalCost, totalValue float64
+	for _, ticker := range tickers {
+		shares, cost := f.holdings[pid][ticker][0], f.holdings[pid][ticker][1]
+		value := shares * 200
+		totalCost += cost
+		totalValue += value
+		fmt.Fprintf(&b, `<tr><td><a>%s</a></td><td>Company</td><td>%g</td><td>%s</td><td>%s</td><td>%s</td><td>%.2f%%</td></tr>`,
+			ticker, shares, money(cost), money(value), money(value-cost), (value-cost)/cost*100)
+	}
+	fmt.Fprintf(&b, `<tr><td>Total</td><td></td><td></td><td>%s</td><td>%s</td><td>%s</td><td></td></tr>`, money(totalCost), money(totalValue), money(totalValue-totalCost))
+	b.WriteString(`</table></body></html>`)
+	return b.String()
+}
+
+func money(value float64) string {
+	s := strconv.FormatFloat(value, 'f', 2, 64)
+	if value >= 1000 {
+		s = s[:len(s)-6] + "," + s[len(s)-6:]
+	}
+	return s
+}
+
+func TestPortfolio(t *testing.T) {
+	fake := newFakeFinviz()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c := New(&Config{userAgent: "finviz-test", baseURL: server.URL})
+	_, err := c.GetPortfolios()
+	require.IsType(t, utils.NotAuthenticatedError(""), err)
+	require.IsType(t, utils.NotAuthenticatedError(""), c.AddPosition(101, Position{Ticker: "AAPL", Shares: 1, Price: 100}))
+
+	err = c.Login(fakeEmail, "wrong")
+	require.IsType(t, utils.NotAuthenticatedError(""), err)
+	require.False(t, c.Authenticated())
+
+	require.Nil(t, c.Login(fakeEmail, fakePassword))
+	require.True(t, c.Authenticated())
+
+	portfolios, err := c.GetPortfolios()
+	require.Nil(t, err)
+	require.Equal(t, []Portfolio{{ID: 101, Name: "Growth"}, {ID: 102, Name: "Dividends"}}, portfolios)
+
+	df, err := c.GetHoldings(101)
+	require.Nil(t, err)
+	require.Equal(t, HoldingHeaders, df.Names())
+	require.Equal(t, 2, df.Nrow())
+	require.Equal(t, []string{"AAPL", "MSFT"}, df.Col("Ticker").Records())
+	require.Equal(t, []float64{10, 5}, df.Col("Shares").Float())
+	require.Equal(t, []float64{1500, 1250}, df.Col("Cost").Float())
+	require.Equal(t, []float64{2000, 1000}, df.Col("Value").Float())
+	require.Equal(t, []float64{500, -250}, df.Col("Gain").Float())
+	require.InDelta(t, 0.3333, df.Col("Gain %").Elem(0).Float(), 1e-9)
+	require.InDelta(t, -0.2, df.Col("Gain %").Elem(1).Float(), 1e-9)
+
+	df, err = c.GetHoldings(102)
+	require.Nil(t, err)
+	require.Equal(t, HoldingHeaders, df.Names())
+	require.Equal(t, 0, df.Nrow())
+
+	date := time.Date(2022, 1, 14, 12, 0, 0, 0, utils.Eastern)
+	require.Nil(t, c.AddPosition(102, Position{Ticker: "ko", Shares: 20, Price: 59.5, Date: date}))
+	require.Nil(t, c.AddPosition(101, Position{Ticker: "AAPL", Shares: 5, Price: 100}))
+	require.NotNil(t, c.AddPosition(101, Position{Ticker: "AAPL"}))
+	require.NotNil(t, c.AddPosition(101, Position{Ticker: "AAPL", Shares: 1}))
+	require.NotNil(t, c.AddPosition(101, Position{Ticker: "NOT A TICKER", Shares: 1}))
+	require.Equal(t, "add 102 KO 20 59.5 01/14/2022", fake.edits[0])
+
+	df, err = c.GetHoldings(102)
+	require.Nil(t, err)
+	require.Equal(t, []string{"KO"}, df.Col("Ticker").Records())
+	require.Equal(t, []float64{1190}, df.Col("Cost").Float())
+
+	require.Nil(t, c.RemovePosition(101, "msft"))
+	df, err = c.GetHoldings(101)
+	require.Nil(t, err)
+	require.Equal(t, []string{"AAPL"}, df.Col("Ticker").Records())
+	require.Equal(t, []float64{15}, df.Col("Shares").Float())
+
+	require.NotNil(t, c.RemovePosition(999, "AAPL"))
+
+	// An expired session is redirected to the login page
+	require.Nil(t, c.SetSession("expired"))
+	_, err = c.GetHoldings(101)
+	require.IsType(t, utils.NotAuthenticatedError(""), err)
+}
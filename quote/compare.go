@@ -0,0 +1,167 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package quote
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// ComparisonColumn is a quote column ranked by ComparePeers
+type ComparisonColumn struct {
+	Name           string
+	Group          string
+	HigherIsBetter bool
+}
+
+// ComparisonColumns lists the valuation, growth and profitability columns ranked by ComparePeers
+var ComparisonColumns = []ComparisonColumn{
+	{Name: "P/E", Group: "valuation"},
+	{Name: "Forward P/E", Group: "valuation"},
+	{Name: "PEG", Group: "valuation"},
+	{Name: "P/S", Group: "valuation"},
+	{Name: "P/B", Group: "valuation"},
+	{Name: "P/C", Group: "valuation"},
+	{Name: "P/FCF", Group: "valuation"},
+	{Name: "EPS this Y", Group: "growth", HigherIsBetter: true},
+	{Name: "EPS growth next Y", Group: "growth", HigherIsBetter: true},
+	{Name: "EPS next 5Y", Group: "growth", HigherIsBetter: true},
+	{Name: "EPS past 5Y", Group: "growth", HigherIsBetter: true},
+	{Name: "Sales past 5Y", Group: "growth", HigherIsBetter: true},
+	{Name: "EPS Q/Q", Group: "growth", HigherIsBetter: true},
+	{Name: "Sales Q/Q", Group: "growth", HigherIsBetter: true},
+	{Name: "ROA", Group: "profitability", HigherIsBetter: true},
+	{Name: "ROE", Group: "profitability", HigherIsBetter: true},
+	{Name: "ROI", Group: "profitability", HigherIsBetter: true},
+	{Name: "Gross Margin", Group: "profitability", HigherIsBetter: true},
+	{Name: "Oper. Margin", Group: "profitability", HigherIsBetter: true},
+	{Name: "Profit Margin", Group: "profitability", HigherIsBetter: true},
+}
+
+// GetPeerComparison returns the comparison table of the provided ticker and its Finviz peers
+func (c *Client) GetPeerComparison(ticker string) (*dataframe.DataFrame, error) {
+	related, err := c.GetRelated(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if len(related.Peers) == 0 {
+		return nil, fmt.Errorf("error no peers listed for ticker '%s'", related.Ticker)
+	}
+
+	results, err := c.GetQuotes(append([]string{related.Ticker}, related.Peers...))
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Errors) > 0 {
+		return nil, fmt.Errorf("error getting quote for ticker '%s': %v", results.Errors[0].Ticker, results.Errors[0].Error)
+	}
+
+	return ComparePeers(results.Data, related.Ticker)
+}
+
+// ComparePeers ranks each comparison column of a quote DataFrame. The subject ticker is listed first and marked
+// with an asterisk. Rank 1 is the best value, and a percentile of 1 beats every peer.
+func ComparePeers(df *dataframe.DataFrame, subject string) (*dataframe.DataFrame, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+
+	tickers := df.Col("Ticker").Records()
+	subjectIndex := -1
+	for i, ticker := range tickers {
+		if ticker == subject {
+			subjectIndex = i
+		}
+	}
+	if subjectIndex < 0 {
+		return nil, fmt.Errorf("error subject ticker '%s' not found in quotes", subject)
+	}
+
+	// Subject first, peers in their listed order
+	order := []int{subjectIndex}
+	for i := range tickers {
+		if i != subjectIndex {
+			order = append(order, i)
+		}
+	}
+	df2 := df.Subset(order)
+
+	marks := make([]string, len(order))
+	marks[0] = "*"
+	columns := []series.Series{
+		df2.Col("Ticker"),
+		series.New(marks, series.String, "Subject"),
+	}
+	if hasColumn(df2, "Company") {
+		columns = append(columns, df2.Col("Company"))
+	}
+
+	for _, column := range ComparisonColumns {
+		if !hasColumn(df2, column.Name) {
+			continue
+		}
+		values := df2.Col(column.Name).Float()
+		ranks, percentiles := rankValues(values, column.HigherIsBetter)
+		columns = append(columns,
+			series.New(values, series.Float, column.Name),
+			series.New(ranks, series.Float, fmt.Sprintf("%s Rank", column.Name)),
+			series.New(percentiles, series.Float, fmt.Sprintf("%s Percentile", column.Name)),
+		)
+	}
+
+	result := dataframe.New(columns...)
+	return &result, result.Error()
+}
+
+// rankValues ranks values from best (1) to worst, with ties sharing the best rank. NaN values are not ranked.
+func rankValues(values []float64, higherIsBetter bool) (ranks, percentiles []float64) {
+	var valid []float64
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			valid = append(valid, v)
+		}
+	}
+	sort.Float64s(valid)
+	if higherIsBetter {
+		sort.Sort(sort.Reverse(sort.Float64Slice(valid)))
+	}
+
+	ranks = make([]float64, len(values))
+	percentiles = make([]float64, len(values))
+	for i, v := range values {
+		if math.IsNaN(v) {
+			ranks[i], percentiles[i] = math.NaN(), math.NaN()
+			continue
+		}
+
+		rank := sort.Search(len(valid), func(j int) bool {
+			if higherIsBetter {
+				return valid[j] <= v
+			}
+			return valid[j] >= v
+		}) + 1
+		ranks[i] = float64(rank)
+		if len(valid) == 1 {
+			percentiles[i] = 1
+		} else {
+			percentiles[i] = float64(len(valid)-rank) / float64(len(valid)-1)
+		}
+	}
+	return ranks, percentiles
+}
+
+func hasColumn(df dataframe.DataFrame, name string) bool {
+	for _, n := range df.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
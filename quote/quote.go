@@ -39,7 +39,8 @@ type Config struct {
 
 type Client struct {
 	*http.Client
-	config Config
+	config    Config
+	validator Validator
 }
 
 func New(config *Config) *Client {
@@ -76,7 +77,11 @@ func (c *Client) RandomizeUserAgent() {
 }
 
 func GenerateURL(ticker string) (string, error) {
-	return fmt.Sprintf("%s?t=%s&ty=c&p=d&b=1", APIURL, strings.ToUpper(ticker)), nil
+	normalized, err := NormalizeTicker(ticker)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?t=%s&ty=c&p=d&b=1", APIURL, normalized), nil
 }
 
 type response struct {
@@ -102,14 +107,34 @@ type Results struct {
 }
 
 func (c *Client) GetQuotes(tickers []string) (finalResults Results, err error) {
+	// Malformed, duplicate and unlisted tickers are rejected before fetching
+	var inputs, normalized, seen []string
+	for _, ticker := range tickers {
+		t, err := NormalizeTicker(ticker)
+		if err != nil {
+			finalResults.Warnings = append(finalResults.Warnings, Warning{Ticker: ticker, Error: err})
+			continue
+		}
+		if utils.Contains(seen, t) {
+			continue
+		}
+		seen = append(seen, t)
+		if c.validator != nil && !c.validator.Contains(t) {
+			finalResults.Warnings = append(finalResults.Warnings, Warning{Ticker: ticker, Error: utils.TickerNotFoundError(fmt.Sprintf("error ticker '%s' not found in universe", t))})
+			continue
+		}
+		inputs = append(inputs, ticker)
+		normalized = append(normalized, t)
+	}
+
 	var wg sync.WaitGroup
-	resultCount := len(tickers)
+	resultCount := len(normalized)
 	rawResults := make([]chan response, resultCount)
 	for i := range rawResults {
 		rawResults[i] = make(chan response, 1)
 	}
 
-	for i, ticker := range tickers {
+	for i, ticker := range normalized {
 		wg.Add(1)
 		go c.getData(ticker, &wg, &rawResults[i])
 		wg.Wait()
@@ -119,11 +144,11 @@ func (c *Client) GetQuotes(tickers []string) (finalResults Results, err error) {
 	for i := 0; i < resultCount; i++ {
 		r := <-rawResults[i]
 		if r.Warning != nil {
-			finalResults.Warnings = append(finalResults.Warnings, Warning{Ticker: tickers[i], Error: r.Warning})
+			finalResults.Warnings = append(finalResults.Warnings, Warning{Ticker: inputs[i], Error: r.Warning})
 			continue
 		}
 		if r.Error != nil {
-			finalResults.Errors = append(finalResults.Errors, Error{Ticker: tickers[i], Error: r.Error})
+			finalResults.Errors = append(finalResults.Errors, Error{Ticker: inputs[i], Error: r.Error})
 			continue
 		}
 		scrapedResults = append(scrapedResults, *r.Result)
@@ -153,20 +178,44 @@ func (c *Client) getData(ticker string, wg *sync.WaitGroup, result *chan respons
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	body, fetched, warning, err := c.fetch(url)
 	if err != nil {
 		*result <- response{Error: err}
 		return
+	} else if warning != nil {
+		*result <- response{Warning: warning}
+		return
 	}
 
-	var body []byte
-	var warning error
-	if err = backoff.RetryNotify(func() error {
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		*result <- response{Error: err}
+		return
+	}
+
+	dict, err := Scrape(doc, fetched)
+	if err != nil {
+		*result <- response{Error: err}
+		return
+	}
+
+	*result <- response{Result: dict}
+}
+
+// fetch requests a quote page and its server time, retrying while rate limited. A missing page is reported as a warning.
+func (c *Client) fetch(url string) (body []byte, fetched time.Time, warning, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fetched, nil, err
+	}
+
+	err = backoff.RetryNotify(func() error {
 		resp, err := c.Do(req)
 		if err != nil {
 			return backoff.Permanent(err)
 		}
 		defer resp.Body.Close()
+		fetched = utils.FetchTime(resp)
 
 		body, err = io.ReadAll(resp.Body)
 		if err != nil {
@@ -191,31 +240,12 @@ func (c *Client) getData(ticker string, wg *sync.WaitGroup, result *chan respons
 		fmt.Printf("[ERROR]: %v\n", err)
 		fmt.Printf("[WAIT_IN_SECONDS]: %v\n", 2*td.Seconds())
 		time.Sleep(td)
-	}); err != nil {
-		*result <- response{Error: err}
-		return
-	} else if warning != nil {
-		*result <- response{Warning: warning}
-		return
-	}
-
-	doc, err := utils.GenerateDocument(body)
-	if err != nil {
-		*result <- response{Error: err}
-		return
-	}
-
-	dict, err := Scrape(doc)
-	if err != nil {
-		*result <- response{Error: err}
-		return
-	}
-
-	*result <- response{Result: dict}
+	})
+	return body, fetched, warning, err
 }
 
-// Scrape scrapes FinViz views to a KVP map
-func Scrape(doc *goquery.Document) (*map[string]interface{}, error) {
+// Scrape scrapes FinViz views to a KVP map. The fetch time resolves the day of "Today" news.
+func Scrape(doc *goquery.Document, fetched time.Time) (*map[string]interface{}, error) {
 	data := make(map[string]interface{})
 	doc.Find("tr[class=\"table-dark-row\"] > td").Each(func(column int, row *goquery.Selection) {
 		if column%2 == 0 {
@@ -276,15 +306,22 @@ func Scrape(doc *goquery.Document) (*map[string]interface{}, error) {
 	// News
 	newsData := doc.Find("#news-table").Find("tbody")
 	var news []map[string]string
-	var date string
-	var datetime string
+	var day string
 	var newsGain string
-	newsData.Children().Each(func(i int, rowNode *goquery.Selection) {
-		if rowNode.Find("td").Eq(0).AttrOr("style", "") != "" {
-			date = rowNode.Find("td").Eq(0).Text()[0:9]
-			datetime = rowNode.Find("td").Eq(0).Text()[0:17]
-		} else {
-			datetime = fmt.Sprintf("%s %s", date, rowNode.Find("td").Eq(0).Text()[0:7])
+	var err error
+	newsData.Children().EachWithBreak(func(i int, rowNode *goquery.Selection) bool {
+		// Rows either carry a full date and time, or just the time of the previous row's date
+		fields := strings.Fields(rowNode.Find("td").Eq(0).Text())
+		if len(fields) == 2 {
+			day = fields[0]
+		} else if len(fields) != 1 || day == "" {
+			err = fmt.Errorf("error unexpected news date '%s'", rowNode.Find("td").Eq(0).Text())
+			return false
+		}
+
+		var datetime time.Time
+		if datetime, err = utils.ParseNewsDate(fmt.Sprintf("%s %s", day, fields[len(fields)-1]), fetched); err != nil {
+			return false
 		}
 
 		newsGain = ""
@@ -293,13 +330,17 @@ func Scrape(doc *goquery.Document) (*map[string]interface{}, error) {
 		}
 
 		news = append(news, map[string]string{
-			"Datetime":  datetime,
+			"Datetime":  datetime.Format(time.RFC3339),
 			"Link":      rowNode.Find("td").Eq(1).Find("a").AttrOr("href", ""),
-			"Source":    rowNode.Find("td").Eq(1).Find("span").Eq(0).Text()[1:],
+			"Source":    strings.TrimSpace(rowNode.Find("td").Eq(1).Find("span").Eq(0).Text()),
 			"Title":     rowNode.Find("td").Eq(1).Find("a").Text(),
 			"News Gain": newsGain,
 		})
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 	data["News"] = news
 
 	// Description
@@ -7,6 +7,7 @@ package quote
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/corpix/uarand"
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
 	"github.com/stretchr/testify/require"
 
 	"github.com/d3an/finviz/utils"
@@ -177,3 +179,141 @@ func TestGetData(t *testing.T) {
 		}()
 	}
 }
+
+func TestGetRelated(t *testing.T) {
+	func() {
+		r, err := recorder.New("cassettes/issue_aapl")
+		require.Nil(t, err)
+		defer func() {
+			err = r.Stop()
+			require.Nil(t, err)
+		}()
+		client := newTestClient(&Config{recorder: r, userAgent: uarand.GetRandom()})
+
+		// The recorded quote page predates the peers and holdings links, so none are scraped from it
+		related, err := client.GetRelated("AAPL")
+		require.Nil(t, err)
+		require.Equal(t, "AAPL", related.Ticker)
+		require.Empty(t, related.Peers)
+		require.Empty(t, related.Holdings)
+		require.True(t, math.IsNaN(related.ExpenseRatio))
+		require.Equal(t, int64(0), related.AUM)
+		require.Equal(t, "", related.Category)
+		require.False(t, related.IsETF())
+	}()
+}
+
+func TestScrapeRelated(t *testing.T) {
+	values := []struct {
+		html     string
+		expected Related
+	}{
+		{
+			html: `<table class="fullview-title"><tr><td><a id="ticker">AAPL</a> <span>[NASD]</span></td></tr></table>
+				<table class="fullview-links"><tr><td><a href="screener.ashx?v=111&t=MSFT,GOOGL,AAPL,META" class="tab-link">Peers</a></td></tr></table>
+				<table class="snapshot-table2"><tr class="table-dark-row"><td>P/E</td><td><b>31.44</b></td></tr></table>`,
+			expected: Related{Ticker: "AAPL", Peers: []string{"MSFT", "GOOGL", "META"}},
+		},
+		{
+			html: `<table class="fullview-title"><tr><td><a id="ticker">SPY</a> <span>[NYSE]</span></td></tr></table>
+				<table class="fullview-links"><tr><td><a href="screener.ashx?v=111&t=AAPL,MSFT,AMZN" class="tab-link">Holdings</a></td></tr></table>
+				<table class="snapshot-table2"><tr class="table-dark-row"><td>Category</td><td><b>Large Cap</b></td><td>Expense</td><td><b>0.09%</b></td><td>AUM</td><td><b>412.34B</b></td></tr></table>`,
+			expected: Related{Ticker: "SPY", Holdings: []string{"AAPL", "MSFT", "AMZN"}, ExpenseRatio: 0.0009, AUM: 412340000000, Category: "Large Cap"},
+		},
+	}
+
+	for _, v := range values {
+		doc, err := utils.GenerateDocument(v.html)
+		require.Nil(t, err)
+
+		related, err := ScrapeRelated(doc)
+		require.Nil(t, err)
+		require.Equal(t, v.expected.Ticker, related.Ticker)
+		require.Equal(t, v.expected.Peers, related.Peers)
+		require.Equal(t, v.expected.Holdings, related.Holdings)
+		require.Equal(t, v.expected.AUM, related.AUM)
+		require.Equal(t, v.expected.Category, related.Category)
+		if v.expected.Holdings == nil {
+			require.False(t, related.IsETF())
+		} else {
+			require.True(t, related.IsETF())
+			require.InDelta(t, v.expected.ExpenseRatio, related.ExpenseRatio, 1e-9)
+		}
+	}
+}
+
+func TestComparePeers(t *testing.T) {
+	df := dataframe.LoadRecords([][]string{
+		{"Ticker", "Company", "P/E", "ROE"},
+		{"MSFT", "Microsoft Corporation", "35.00", "45.00%"},
+		{"AAPL", "Apple Inc.", "30.00", "150.00%"},
+		{"GOOGL", "Alphabet Inc.", "25.00", "-"},
+		{"META", "Meta Platforms, Inc.", "30.00", "25.00%"},
+	})
+	df = *utils.CleanFinvizDataFrame(&df)
+
+	comparison, err := ComparePeers(&df, "AAPL")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Ticker", "Subject", "Company", "P/E", "P/E Rank", "P/E Percentile", "ROE", "ROE Rank", "ROE Percentile"}, comparison.Names())
+	require.Equal(t, []string{"AAPL", "MSFT", "GOOGL", "META"}, comparison.Col("Ticker").Records())
+	require.Equal(t, []string{"*", "", "", ""}, comparison.Col("Subject").Records())
+	require.Equal(t, []float64{2, 4, 1, 2}, comparison.Col("P/E Rank").Float())
+	require.Equal(t, []float64{2.0 / 3.0, 0, 1, 2.0 / 3.0}, comparison.Col("P/E Percentile").Float())
+
+	roeRanks := comparison.Col("ROE Rank").Float()
+	require.Equal(t, []float64{1, 2}, roeRanks[:2])
+	require.True(t, math.IsNaN(roeRanks[2]))
+	require.Equal(t, 3.0, roeRanks[3])
+
+	_, err = ComparePeers(&df, "TSLA")
+	require.NotNil(t, err)
+}
+
+func TestNormalizeTicker(t *testing.T) {
+	values := []struct {
+		ticker   string
+		expected string
+		valid    bool
+	}{
+		{ticker: " aapl ", expected: "AAPL", valid: true},
+		{ticker: "brk.b", expected: "BRK-B", valid: true},
+		{ticker: "BRK/B", expected: "BRK-B", valid: true},
+		{ticker: "$bf-b", expected: "BF-B", valid: true},
+		{ticker: "", valid: false},
+		{ticker: "AAPL;DROP", valid: false},
+		{ticker: "TOOLONGTICKER", valid: false},
+	}
+
+	for _, v := range values {
+		normalized, err := NormalizeTicker(v.ticker)
+		if !v.valid {
+			require.IsType(t, utils.InvalidTickerError(""), err)
+			continue
+		}
+		require.Nil(t, err)
+		require.Equal(t, v.expected, normalized)
+	}
+
+	normalized, err := NormalizeTickers([]string{"aapl", "brk.b", "AAPL", "BRK/B"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"AAPL", "BRK-B"}, normalized)
+}
+
+func TestGetQuotesValidation(t *testing.T) {
+	universe := dataframe.LoadRecords([][]string{{"No.", "Ticker"}, {"1", "AAPL"}, {"2", "BRK-B"}})
+	set, err := NewTickerSet(&universe)
+	require.Nil(t, err)
+	require.True(t, set.Contains("BRK-B"))
+
+	client := newTestClient(nil)
+	client.SetValidator(TickerSet{})
+
+	results, err := client.GetQuotes([]string{"not a ticker!", "ZZZZ", "zzzz"})
+	require.Nil(t, err)
+	require.Nil(t, results.Errors)
+	require.Len(t, results.Warnings, 2)
+	require.IsType(t, utils.InvalidTickerError(""), results.Warnings[0].Error)
+	require.Equal(t, "ZZZZ", results.Warnings[1].Ticker)
+	require.IsType(t, utils.TickerNotFoundError(""), results.Warnings[1].Error)
+	require.Equal(t, 0, results.Data.Nrow())
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package quote
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/d3an/finviz/utils"
+)
+
+// Related holds the peers of a stock, or the holdings and fund details of an ETF, listed on a quote page
+type Related struct {
+	Ticker   string
+	Peers    []string
+	Holdings []string
+	// ExpenseRatio is a fraction, i.e. 0.0009 for 0.09%, and NaN when not listed
+	ExpenseRatio float64
+	// AUM is the assets under management in dollars, and 0 when not listed
+	AUM      int64
+	Category string
+}
+
+// IsETF reports whether the quote page listed fund details
+func (r *Related) IsETF() bool {
+	return len(r.Holdings) > 0 || r.Category != "" || !math.IsNaN(r.ExpenseRatio)
+}
+
+// GetRelated returns the peers, or the ETF holdings and fund details, of the provided ticker
+func (c *Client) GetRelated(ticker string) (*Related, error) {
+	url, err := GenerateURL(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, warning, err := c.fetch(url)
+	if err != nil {
+		return nil, err
+	} else if warning != nil {
+		return nil, fmt.Errorf("error getting ticker '%s': %v", ticker, warning)
+	}
+
+	doc, err := utils.GenerateDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ScrapeRelated(doc)
+}
+
+// ScrapeRelated scrapes the peer and ETF details from a quote page
+func ScrapeRelated(doc *goquery.Document) (*Related, error) {
+	related := &Related{
+		Ticker:       strings.TrimSpace(doc.Find("#ticker").Text()),
+		ExpenseRatio: math.NaN(),
+	}
+	if related.Ticker == "" {
+		return nil, fmt.Errorf("error ticker not found on quote page")
+	}
+
+	// Peers and holdings link to a screener of the listed tickers, i.e. screener.ashx?t=MSFT,GOOGL
+	doc.Find(".fullview-links a").Each(func(i int, link *goquery.Selection) {
+		switch strings.TrimSpace(link.Text()) {
+		case "Peers":
+			related.Peers = linkedTickers(link.AttrOr("href", ""), related.Ticker)
+		case "Holdings":
+			related.Holdings = linkedTickers(link.AttrOr("href", ""), related.Ticker)
+		}
+	})
+
+	var err error
+	doc.Find("tr[class=\"table-dark-row\"] > td").EachWithBreak(func(column int, row *goquery.Selection) bool {
+		if column%2 != 0 {
+			return true
+		}
+		value := strings.TrimSpace(row.Next().Text())
+		if value == "" || value == "-" {
+			return true
+		}
+
+		switch strings.TrimSpace(row.Text()) {
+		case "Expense":
+			var expense float64
+			if expense, err = strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64); err != nil {
+				err = fmt.Errorf("error parsing expense ratio '%s': %v", value, err)
+				return false
+			}
+			related.ExpenseRatio = expense / 100.0
+		case "AUM":
+			if related.AUM, err = parseDollars(value); err != nil {
+				err = fmt.Errorf("error parsing AUM '%s': %v", value, err)
+				return false
+			}
+		case "Category":
+			related.Category = value
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
+
+// linkedTickers returns the tickers of a screener link, excluding the quote page's own ticker
+func linkedTickers(href, ticker string) []string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil
+	}
+
+	var tickers []string
+	for _, t := range strings.Split(u.Query().Get("t"), ",") {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t != "" && t != ticker && !utils.Contains(tickers, t) {
+			tickers = append(tickers, t)
+		}
+	}
+	return tickers
+}
+
+// parseDollars parses amounts such as 412.34B or 850.5M
+func parseDollars(value string) (int64, error) {
+	multiple := 1.0
+	switch {
+	case strings.HasSuffix(value, "T"):
+		multiple = 1e12
+	case strings.HasSuffix(value, "B"):
+		multiple = 1e9
+	case strings.HasSuffix(value, "M"):
+		multiple = 1e6
+	case strings.HasSuffix(value, "K"):
+		multiple = 1e3
+	}
+	if multiple != 1.0 {
+		value = value[:len(value)-1]
+	}
+
+	num, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(num * multiple)), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package quote
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+var (
+	tickerPattern         = regexp.MustCompile(`^[A-Z0-9]{1,8}(-[A-Z0-9]{1,3})?$`)
+	classSeparatorPattern = regexp.MustCompile(`[./_ ]+`)
+)
+
+// NormalizeTicker trims and upper-cases a ticker, and rewrites class share separators to Finviz's dash,
+// i.e. " brk.b", "BRK/B" and "$brk-b" all become "BRK-B"
+func NormalizeTicker(ticker string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(ticker), "$"))
+	normalized = classSeparatorPattern.ReplaceAllString(normalized, "-")
+	if !tickerPattern.MatchString(normalized) {
+		return "", utils.InvalidTickerError(fmt.Sprintf("error invalid ticker '%s'", ticker))
+	}
+	return normalized, nil
+}
+
+// NormalizeTickers normalizes tickers and removes duplicates, keeping the first occurrence
+func NormalizeTickers(tickers []string) ([]string, error) {
+	var normalized []string
+	for _, ticker := range tickers {
+		t, err := NormalizeTicker(ticker)
+		if err != nil {
+			return nil, err
+		}
+		if !utils.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized, nil
+}
+
+// Validator reports whether a normalized ticker is listed, so unknown tickers are rejected before fetching
+type Validator interface {
+	Contains(ticker string) bool
+}
+
+// SetValidator sets the ticker universe GetQuotes validates against. A nil Validator disables validation.
+func (c *Client) SetValidator(v Validator) {
+	c.validator = v
+}
+
+// TickerSet is a Validator of the tickers listed in screener results
+type TickerSet map[string]struct{}
+
+// NewTickerSet builds a TickerSet from the Ticker column of a screener DataFrame
+func NewTickerSet(df *dataframe.DataFrame) (TickerSet, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+	for _, name := range df.Names() {
+		if name != "Ticker" {
+			continue
+		}
+
+		set := make(TickerSet)
+		for _, ticker := range df.Col("Ticker").Records() {
+			if normalized, err := NormalizeTicker(ticker); err == nil {
+				set[normalized] = struct{}{}
+			}
+		}
+		return set, nil
+	}
+	return nil, fmt.Errorf("error DataFrame has no Ticker column")
+}
+
+// Contains reports whether the ticker is in the set
+func (s TickerSet) Contains(ticker string) bool {
+	_, exists := s[ticker]
+	return exists
+}
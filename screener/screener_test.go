@@ -289,3 +289,33 @@ func TestCleanDataFrame(t *testing.T) {
 		}()
 	}
 }
+
+func TestGenerateSignalURL(t *testing.T) {
+	values := []struct {
+		signal   string
+		filters  []string
+		expected string
+	}{
+		{"ta_unusualvolume", nil, APIURL + "?v=111&s=ta_unusualvolume"},
+		{"Top Gainers", []string{"exch_nasd|nyse", "sh_avgvol_o50"}, APIURL + "?v=111&s=ta_topgainers&f=exch_nasd|nyse,sh_avgvol_o50"},
+		{"channelup", []string{"cap_large"}, APIURL + "?v=111&s=ta_p_channelup&f=cap_large"},
+		{"head & shoulders inverse", nil, APIURL + "?v=111&s=ta_p_headandshouldersinv"},
+	}
+	for _, v := range values {
+		url, err := GenerateSignalURL(v.signal, v.filters...)
+		require.Nil(t, err)
+		require.Equal(t, v.expected, url)
+	}
+
+	_, err := GenerateSignalURL("ta_cupandhandle")
+	require.IsType(t, utils.ErrorSignalNotFound(""), err)
+	_, err = New(nil).RunSignal("not a signal")
+	require.IsType(t, utils.ErrorSignalNotFound(""), err)
+	_, err = GenerateSignalURL("topgainers", "cap_large&o=price")
+	require.NotNil(t, err)
+
+	require.Equal(t, len(Signals), len(SignalLookup))
+	df := SignalDataFrame()
+	require.Equal(t, len(Signals), df.Nrow())
+	require.Equal(t, []string{"Signal", "Name", "Query", "Description"}, df.Names())
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package screener
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL = "https://finviz.com/screener.ashx"
+)
+
+// Signal is a screener signal, selected by the s query parameter
+type Signal struct {
+	// Name is the name Finviz lists the signal by, i.e. "Top Gainers"
+	Name string
+	// Query is the s query parameter, i.e. "ta_topgainers"
+	Query       string
+	Description string
+}
+
+// Signals lists the screener signals, in the order of the screener's signal menu
+var Signals = []Signal{
+	{"Top Gainers", "ta_topgainers", "Stocks with the largest percentage gain today"},
+	{"Top Losers", "ta_toplosers", "Stocks with the largest percentage loss today"},
+	{"New High", "ta_newhigh", "Stocks making a new 52-week high today"},
+	{"New Low", "ta_newlow", "Stocks making a new 52-week low today"},
+	{"Most Volatile", "ta_mostvolatile", "Stocks with the highest volatility over the past week"},
+	{"Most Active", "ta_mostactive", "Stocks with the highest volume today"},
+	{"Unusual Volume", "ta_unusualvolume", "Stocks trading well above their average volume today"},
+	{"Overbought", "ta_overbought", "Stocks with an RSI(14) above 70"},
+	{"Oversold", "ta_oversold", "Stocks with an RSI(14) below 30"},
+	{"Downgrades", "n_downgrades", "Stocks downgraded by an analyst today"},
+	{"Upgrades", "n_upgrades", "Stocks upgraded by an analyst today"},
+	{"Earnings Before", "n_earningsbefore", "Stocks reporting earnings today before market open"},
+	{"Earnings After", "n_earningsafter", "Stocks reporting earnings today after market close"},
+	{"Insider Buying", "it_latestbuys", "Stocks with the latest insider purchases"},
+	{"Insider Selling", "it_latestsales", "Stocks with the latest insider sales"},
+	{"Major News", "n_majornews", "Stocks with the most news coverage today"},
+	{"Horizontal S/R", "ta_p_horizontal", "Stocks trading between horizontal support and resistance"},
+	{"TL Resistance", "ta_p_tlresistance", "Stocks testing a trendline resistance"},
+	{"TL Support", "ta_p_tlsupport", "Stocks testing a trendline support"},
+	{"Wedge Up", "ta_p_wedgeup", "Stocks forming a rising wedge pattern"},
+	{"Wedge Down", "ta_p_wedgedown", "Stocks forming a falling wedge pattern"},
+	{"Triangle Ascending", "ta_p_wedgeresistance", "Stocks forming an ascending triangle pattern"},
+	{"Triangle Descending", "ta_p_wedgesupport", "Stocks forming a descending triangle pattern"},
+	{"Wedge", "ta_p_wedge", "Stocks forming a symmetrical wedge pattern"},
+	{"Channel Up", "ta_p_channelup", "Stocks trading in a rising channel"},
+	{"Channel Down", "ta_p_channeldown", "Stocks trading in a falling channel"},
+	{"Channel", "ta_p_channel", "Stocks trading in a horizontal channel"},
+	{"Double Top", "ta_p_doubletop", "Stocks forming a double top pattern"},
+	{"Double Bottom", "ta_p_doublebottom", "Stocks forming a double bottom pattern"},
+	{"Multiple Top", "ta_p_multipletop", "Stocks forming a multiple top pattern"},
+	{"Multiple Bottom", "ta_p_multiplebottom", "Stocks forming a multiple bottom pattern"},
+	{"Head & Shoulders", "ta_p_headandshoulders", "Stocks forming a head and shoulders pattern"},
+	{"Head & Shoulders Inverse", "ta_p_headandshouldersinv", "Stocks forming an inverse head and shoulders pattern"},
+}
+
+// SignalLookup maps the short names of the Signals, i.e. "topgainers" or "channelup", to their signal
+var SignalLookup = func() map[string]Signal {
+	lookup := make(map[string]Signal, len(Signals))
+	for _, signal := range Signals {
+		lookup[signalKey(signal.Name)] = signal
+	}
+	return lookup
+}()
+
+// signalKey returns the short name of a signal, the lowercase letters and digits of its name
+func signalKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return -1
+		}
+	}, name)
+}
+
+// LookupSignal returns the signal with a name, short name or s query parameter, i.e. "Top Gainers", "topgainers"
+// or "ta_topgainers"
+func LookupSignal(name string) (Signal, error) {
+	for _, signal := range Signals {
+		if strings.EqualFold(signal.Query, strings.TrimSpace(name)) {
+			return signal, nil
+		}
+	}
+	if signal, exists := SignalLookup[signalKey(name)]; exists {
+		return signal, nil
+	}
+	return Signal{}, utils.ErrorSignalNotFound(fmt.Sprintf("error signal '%s' not found", name))
+}
+
+// SignalDataFrame returns the Signals with their short names, i.e. to list them
+func SignalDataFrame() *dataframe.DataFrame {
+	rows := [][]string{{"Signal", "Name", "Query", "Description"}}
+	for _, signal := range Signals {
+		rows = append(rows, []string{signalKey(signal.Name), signal.Name, signal.Query, signal.Description})
+	}
+	df := dataframe.LoadRecords(rows)
+	return &df
+}
+
+// GenerateSignalURL returns the screener URL of a signal, narrowed by Finviz filters, i.e. "cap_large" or
+// "exch_nasd|nyse"
+func GenerateSignalURL(signal string, filters ...string) (string, error) {
+	s, err := LookupSignal(signal)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s?v=111&s=%s", APIURL, s.Query)
+
+	for _, filter := range filters {
+		if strings.TrimSpace(filter) == "" || strings.ContainsAny(filter, ",&= ") {
+			return "", fmt.Errorf("error filter '%s' is not a Finviz filter, i.e. 'cap_large'", filter)
+		}
+	}
+	if len(filters) > 0 {
+		url += "&f=" + strings.Join(filters, ",")
+	}
+	return url, nil
+}
+
+// RunSignal returns the overview of the stocks of a signal, narrowed by Finviz filters
+func (c *Client) RunSignal(signal string, filters ...string) (*dataframe.DataFrame, error) {
+	url, err := GenerateSignalURL(signal, filters...)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetScreenerResults(url)
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package universe
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/pkg/errors"
+
+	"github.com/d3an/finviz/utils"
+)
+
+const (
+	APIURL        = "https://finviz.com/screener.ashx?v=111"
+	DefaultMaxAge = 24 * time.Hour
+)
+
+// ExchangeFilters maps each exchange to the screener filter listing its tickers
+var ExchangeFilters = map[string]string{
+	"AMEX":   "exch_amex",
+	"NASDAQ": "exch_nasd",
+	"NYSE":   "exch_nyse",
+}
+
+// Screener runs a Finviz screen, i.e. *screener.Client
+type Screener interface {
+	GetScreenerResults(url string) (*dataframe.DataFrame, error)
+}
+
+// Entry is a single listed ticker
+type Entry struct {
+	Ticker    string `json:"ticker"`
+	Company   string `json:"company"`
+	Sector    string `json:"sector"`
+	Industry  string `json:"industry"`
+	Country   string `json:"country"`
+	Exchange  string `json:"exchange"`
+	MarketCap int64  `json:"market_cap"`
+}
+
+// Universe is the ticker master table of every listed ticker
+type Universe struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Entries   []Entry   `json:"entries"`
+	index     map[string]int
+}
+
+// New creates a Universe of the provided entries, sorted by ticker
+func New(entries []Entry, updatedAt time.Time) *Universe {
+	u := &Universe{UpdatedAt: updatedAt, Entries: entries}
+	u.reindex()
+	return u
+}
+
+// Build screens every exchange with the overview view to create a Universe
+func Build(s Screener) (*Universe, error) {
+	exchanges := make([]string, 0, len(ExchangeFilters))
+	for exchange := range ExchangeFilters {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+
+	var entries []Entry
+	for _, exchange := range exchanges {
+		df, err := s.GetScreenerResults(fmt.Sprintf("%s&f=%s", APIURL, ExchangeFilters[exchange]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error building universe for exchange '%s'", exchange)
+		}
+
+		results, err := EntriesFromDataFrame(df, exchange)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, results...)
+	}
+
+	return New(entries, time.Now()), nil
+}
+
+// EntriesFromDataFrame converts overview screener results to entries of the provided exchange
+func EntriesFromDataFrame(df *dataframe.DataFrame, exchange string) ([]Entry, error) {
+	if df.Error() != nil {
+		return nil, df.Error()
+	}
+
+	columns := make(map[string][]string)
+	for _, name := range []string{"Ticker", "Company", "Sector", "Industry", "Country"} {
+		if !utils.Contains(df.Names(), name) {
+			return nil, fmt.Errorf("error screener results missing column '%s'", name)
+		}
+		columns[name] = df.Col(name).Records()
+	}
+
+	var marketCaps []float64
+	if utils.Contains(df.Names(), "Market Cap") {
+		marketCaps = df.Col("Market Cap").Float()
+	}
+
+	entries := make([]Entry, df.Nrow())
+	for i := range entries {
+		entries[i] = Entry{
+			Ticker:   columns["Ticker"][i],
+			Company:  columns["Company"][i],
+			Sector:   columns["Sector"][i],
+			Industry: columns["Industry"][i],
+			Country:  columns["Country"][i],
+			Exchange: exchange,
+		}
+		if marketCaps != nil && !math.IsNaN(marketCaps[i]) {
+			entries[i].MarketCap = int64(marketCaps[i])
+		}
+	}
+	return entries, nil
+}
+
+// DefaultPath returns the location of the universe in the user's cache directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "finviz", "universe.json"), nil
+}
+
+// Load reads a Universe saved with Save
+func Load(path string) (*Universe, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var u Universe
+	if err = json.NewDecoder(f).Decode(&u); err != nil {
+		return nil, errors.Wrapf(err, "error decoding universe '%s'", path)
+	}
+	u.reindex()
+	return &u, nil
+}
+
+// Save writes the Universe as json, creating its directory if needed
+func (u *Universe) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(u)
+}
+
+// Open loads the Universe at path, rebuilding and saving it if it is missing or older than maxAge
+func Open(s Screener, path string, maxAge time.Duration) (*Universe, error) {
+	u, err := Load(path)
+	if err == nil && !u.Stale(maxAge) {
+		return u, nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if u, err = Build(s); err != nil {
+		return nil, err
+	}
+	return u, u.Save(path)
+}
+
+// Stale reports whether the Universe is older than maxAge
+func (u *Universe) Stale(maxAge time.Duration) bool {
+	return time.Since(u.UpdatedAt) > maxAge
+}
+
+// Lookup returns the entry of a ticker
+func (u *Universe) Lookup(ticker string) (Entry, bool) {
+	i, exists := u.index[strings.ToUpper(ticker)]
+	if !exists {
+		return Entry{}, false
+	}
+	return u.Entries[i], true
+}
+
+// Contains reports whether a ticker is listed, making the Universe a quote.Validator
+func (u *Universe) Contains(ticker string) bool {
+	_, exists := u.index[strings.ToUpper(ticker)]
+	return exists
+}
+
+// BySector returns the entries of a sector, ignoring case
+func (u *Universe) BySector(sector string) []Entry {
+	return u.filter(func(e Entry) bool { return strings.EqualFold(e.Sector, sector) })
+}
+
+// ByIndustry returns the entries of an industry, ignoring case
+func (u *Universe) ByIndustry(industry string) []Entry {
+	return u.filter(func(e Entry) bool { return strings.EqualFold(e.Industry, industry) })
+}
+
+// ByCountry returns the entries of a country, ignoring case
+func (u *Universe) ByCountry(country string) []Entry {
+	return u.filter(func(e Entry) bool { return strings.EqualFold(e.Country, country) })
+}
+
+// ByExchange returns the entries of an exchange, ignoring case
+func (u *Universe) ByExchange(exchange string) []Entry {
+	return u.filter(func(e Entry) bool { return strings.EqualFold(e.Exchange, exchange) })
+}
+
+func (u *Universe) filter(keep func(e Entry) bool) []Entry {
+	var entries []Entry
+	for _, e := range u.Entries {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (u *Universe) reindex() {
+	sort.SliceStable(u.Entries, func(i, j int) bool {
+		return u.Entries[i].Ticker < u.Entries[j].Ticker
+	})
+	u.index = make(map[string]int, len(u.Entries))
+	for i, e := range u.Entries {
+		u.index[strings.ToUpper(e.Ticker)] = i
+	}
+}
+
+// DataFrame converts entries to a DataFrame
+func DataFrame(entries []Entry) *dataframe.DataFrame {
+	rows := [][]string{{"Ticker", "Company", "Sector", "Industry", "Country", "Exchange", "Market Cap"}}
+	for _, e := range entries {
+		marketCap := "NaN"
+		if e.MarketCap != 0 {
+			marketCap = fmt.Sprintf("%d", e.MarketCap)
+		}
+		rows = append(rows, []string{e.Ticker, e.Company, e.Sector, e.Industry, e.Country, e.Exchange, marketCap})
+	}
+	df := dataframe.LoadRecords(rows)
+	return &df
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package universe
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/d3an/finviz/utils/test"
+)
+
+func newTestScreener() *test.Screener {
+	headers := []string{"No.", "Ticker", "Company", "Sector", "Industry", "Country", "Market Cap", "P/E", "Price", "Change", "Volume"}
+	return &test.Screener{Results: map[string][][]string{
+		fmt.Sprintf("%s&f=exch_amex", APIURL): {headers,
+			{"1", "IMO", "Imperial Oil Limited", "Energy", "Oil & Gas Integrated", "Canada", "30.42B", "14.20", "45.10", "1.20%", "1,025,300"},
+		},
+		fmt.Sprintf("%s&f=exch_nasd", APIURL): {headers,
+			{"1", "AAPL", "Apple Inc.", "Technology", "Consumer Electronics", "USA", "2800.50B", "29.50", "172.10", "0.50%", "80,000,000"},
+			{"2", "MSFT", "Microsoft Corporation", "Technology", "Software - Infrastructure", "USA", "2300.00B", "33.10", "310.20", "-0.20%", "25,000,000"},
+		},
+		fmt.Sprintf("%s&f=exch_nyse", APIURL): {headers,
+			{"1", "BRK-B", "Berkshire Hathaway Inc.", "Financial", "Insurance - Diversified", "USA", "700.00B", "8.10", "320.00", "0.10%", "3,000,000"},
+		},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	u, err := Build(newTestScreener())
+	require.Nil(t, err)
+	require.Len(t, u.Entries, 4)
+
+	entry, exists := u.Lookup("brk-b")
+	require.True(t, exists)
+	require.Equal(t, Entry{
+		Ticker:    "BRK-B",
+		Company:   "Berkshire Hathaway Inc.",
+		Sector:    "Financial",
+		Industry:  "Insurance - Diversified",
+		Country:   "USA",
+		Exchange:  "NYSE",
+		MarketCap: 700000000000,
+	}, entry)
+	require.False(t, u.Contains("ZZZZ"))
+
+	require.Len(t, u.BySector("technology"), 2)
+	require.Len(t, u.ByIndustry("Oil & Gas Integrated"), 1)
+	require.Len(t, u.ByCountry("USA"), 3)
+	require.Len(t, u.ByExchange("nasdaq"), 2)
+	require.Equal(t, 4, DataFrame(u.Entries).Nrow())
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "finviz", "universe.json")
+	s := newTestScreener()
+
+	u, err := Open(s, path, time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, 3, s.Calls)
+
+	// A fresh universe is loaded from disk
+	loaded, err := Open(s, path, time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, 3, s.Calls)
+	require.Equal(t, u.Entries, loaded.Entries)
+	require.True(t, loaded.Contains("AAPL"))
+
+	// A stale universe is rebuilt
+	loaded.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	require.Nil(t, loaded.Save(path))
+	_, err = Open(s, path, time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, 6, s.Calls)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	// Embed the time zone database so America/New_York resolves on systems without one
+	_ "time/tzdata"
+)
+
+// Eastern is the time zone Finviz reports dates in, observing both EST and EDT
+var Eastern = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// FetchTime returns the server time of a response, falling back to the local clock
+func FetchTime(resp *http.Response) time.Time {
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		return date
+	}
+	return time.Now()
+}
+
+// ParseNewsDate parses the dates listed beside Finviz news, relative to the time the page was fetched:
+//
+//	"03:04PM"            today
+//	"Today 03:04PM"      today
+//	"Jan-02"             the latest Jan 2 not after the fetch time
+//	"Jan-02 03:04PM"     the latest Jan 2 not after the fetch time
+//	"Jan-02-06 03:04PM"  an explicit date
+func ParseNewsDate(raw string, fetched time.Time) (time.Time, error) {
+	fetched = fetched.In(Eastern)
+	fields := strings.Fields(raw)
+
+	var day, clock string
+	switch len(fields) {
+	case 1:
+		if strings.HasSuffix(fields[0], "M") {
+			clock = fields[0]
+		} else {
+			day = fields[0]
+		}
+	case 2:
+		day, clock = fields[0], fields[1]
+	default:
+		return time.Time{}, fmt.Errorf("error parsing news date '%s'", raw)
+	}
+
+	hour, minute := 0, 0
+	if clock != "" {
+		t, err := time.Parse("3:04PM", clock)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing news date '%s': %v", raw, err)
+		}
+		hour, minute = t.Hour(), t.Minute()
+	}
+
+	switch {
+	case day == "" || day == "Today":
+		return time.Date(fetched.Year(), fetched.Month(), fetched.Day(), hour, minute, 0, 0, Eastern), nil
+	case len(day) == len("Jan-02-06"):
+		t, err := time.Parse("Jan-02-06", day)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing news date '%s': %v", raw, err)
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, Eastern), nil
+	default:
+		t, err := time.Parse("Jan-02", day)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing news date '%s': %v", raw, err)
+		}
+		date := time.Date(fetched.Year(), t.Month(), t.Day(), hour, minute, 0, 0, Eastern)
+		// News is never dated after the fetch, so a later date belongs to the previous year, i.e. Dec-31 fetched on Jan 1
+		if date.After(fetched.Add(24 * time.Hour)) {
+			date = date.AddDate(-1, 0, 0)
+		}
+		return date, nil
+	}
+}
+
+// ParseCalendarDate parses an economic calendar day, i.e. "Mon Jan 17", and release time, i.e. "8:30 AM"
+func ParseCalendarDate(day, clock string, year int) (time.Time, error) {
+	date, err := time.ParseInLocation("Mon Jan 2 2006 3:04 PM", fmt.Sprintf("%s %d %s", strings.Join(strings.Fields(day), " "), year, strings.TrimSpace(clock)), Eastern)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing calendar date '%s %s': %v", day, clock, err)
+	}
+	return date, nil
+}
@@ -106,3 +106,24 @@ type MethodNotImplementedError string
 func (err MethodNotImplementedError) Error() string {
 	return string(err)
 }
+
+// InvalidTickerError is the error given if a ticker is not a well-formed symbol
+type InvalidTickerError string
+
+func (err InvalidTickerError) Error() string {
+	return string(err)
+}
+
+// TickerNotFoundError is the error given if a ticker is not listed in the ticker universe
+type TickerNotFoundError string
+
+func (err TickerNotFoundError) Error() string {
+	return string(err)
+}
+
+// NotAuthenticatedError is the error given if a request requires a signed in Finviz session
+type NotAuthenticatedError string
+
+func (err NotAuthenticatedError) Error() string {
+	return string(err)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022 James Bury. All rights reserved.
+// Project site: https://github.com/d3an/finviz
+// Use of this source code is governed by a MIT-style license that
+// can be found in the LICENSE file for the project.
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICSEvent is a single VEVENT of an iCalendar file
+type ICSEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	// End is exclusive. All-day events end on the following day.
+	End    time.Time
+	AllDay bool
+}
+
+// icsTimeZone defines America/New_York by its current daylight saving rules
+var icsTimeZone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:America/New_York",
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:-0500",
+	"TZOFFSETTO:-0400",
+	"TZNAME:EDT",
+	"DTSTART:19700308T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:-0400",
+	"TZOFFSETTO:-0500",
+	"TZNAME:EST",
+	"DTSTART:19701101T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+// WriteICS writes events as an iCalendar (RFC 5545) file, with times in America/New_York.
+// The stamp is the DTSTAMP of every event, usually the time the events were fetched.
+func WriteICS(w io.Writer, name string, stamp time.Time, events []ICSEvent) error {
+	writer := bufio.NewWriter(w)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//d3an//finviz//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeICS(name),
+		"X-WR-TIMEZONE:America/New_York",
+	}
+	lines = append(lines, icsTimeZone...)
+
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escapeICS(event.UID),
+			"DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"),
+		)
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format("20060102"),
+				"DTEND;VALUE=DATE:"+event.End.Format("20060102"),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART;TZID=America/New_York:"+event.Start.In(Eastern).Format("20060102T150405"),
+				"DTEND;TZID=America/New_York:"+event.End.In(Eastern).Format("20060102T150405"),
+			)
+		}
+		lines = append(lines, "SUMMARY:"+escapeICS(event.Summary))
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICS(event.Description))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := writer.WriteString(foldICS(line)); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// escapeICS escapes the TEXT value characters of RFC 5545
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICS splits a content line into lines of at most 75 octets, without splitting UTF-8 characters
+func foldICS(line string) string {
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	folded.WriteString("\r\n")
+	return folded.String()
+}
+
+// ICSUID returns a stable event UID of the provided parts
+func ICSUID(parts ...string) string {
+	id := strings.ToLower(strings.Join(parts, "-"))
+	id = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, id)
+	return fmt.Sprintf("%s@finviz.com", id)
+}
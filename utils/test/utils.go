@@ -1,13 +1,21 @@
 package test
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/go-gota/gota/dataframe"
+
+	"github.com/d3an/finviz/utils"
 )
 
+// HomepageCassette is the Finviz homepage recorded by the earnings tests, shared with the home tests, relative to
+// their package
+const HomepageCassette = "../earnings/cassettes/earnings"
+
 // headerTransport implements a Transport that can have its RoundTripper interface modified
 type headerTransport struct {
 	T http.RoundTripper
@@ -57,3 +65,21 @@ func NewClient(config *Config) *Client {
 		},
 	}
 }
+
+// Screener is a fake screener client returning the records of each expected screener URL, headers first
+type Screener struct {
+	Results map[string][][]string
+	// Calls counts the screener requests
+	Calls int
+}
+
+// GetScreenerResults returns the records of the URL as a cleaned screener DataFrame
+func (s *Screener) GetScreenerResults(url string) (*dataframe.DataFrame, error) {
+	s.Calls++
+	records, exists := s.Results[url]
+	if !exists {
+		return nil, fmt.Errorf("unexpected url '%s'", url)
+	}
+	df := dataframe.LoadRecords(records)
+	return utils.CleanFinvizDataFrame(&df), nil
+}
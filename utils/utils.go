@@ -313,6 +313,7 @@ var ColumnTypeLookup = map[string]string{
 	"no":                                "int",
 	"no.":                               "int",
 	"ticker":                            "string",
+	"name":                              "string",
 	"exchange":                          "string",
 	"company":                           "string",
 	"sector":                            "string",
@@ -469,8 +470,10 @@ var ColumnTypeLookup = map[string]string{
 	"relvol":                            "float",
 	"prev close":                        "float",
 	"price":                             "float",
+	"last":                              "float",
 	"change":                            "percent",
 	"volume":                            "commaint",
+	"stocks":                            "commaint",
 	"earnings date":                     "string",
 	"earnings":                          "string",
 	"target price":                      "float",
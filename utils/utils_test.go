@@ -5,6 +5,15 @@
 
 package utils
 
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
 /*
 func TestExportScreenCSV(t *testing.T) {
 	r, err := recorder.New("fixtures/finviz_screener")
@@ -70,3 +79,95 @@ func TestExportScreenJSON(t *testing.T) {
 	}
 }
 */
+
+func TestParseNewsDate(t *testing.T) {
+	values := []struct {
+		raw      string
+		fetched  time.Time
+		expected time.Time
+	}{
+		{ // Time only during daylight time
+			raw:      "06:57PM",
+			fetched:  time.Date(2022, time.June, 21, 23, 0, 0, 0, time.UTC),
+			expected: time.Date(2022, time.June, 21, 18, 57, 0, 0, time.FixedZone("EDT", -4*60*60)),
+		},
+		{ // Time only during standard time
+			raw:      "06:57PM",
+			fetched:  time.Date(2022, time.February, 21, 23, 57, 29, 0, time.UTC),
+			expected: time.Date(2022, time.February, 21, 18, 57, 0, 0, time.FixedZone("EST", -5*60*60)),
+		},
+		{ // Date only, fetched after New Year
+			raw:      "Dec-31",
+			fetched:  time.Date(2022, time.January, 1, 14, 0, 0, 0, time.UTC),
+			expected: time.Date(2021, time.December, 31, 0, 0, 0, 0, Eastern),
+		},
+		{ // Date only, same year
+			raw:      "Feb-21",
+			fetched:  time.Date(2022, time.February, 22, 1, 0, 0, 0, time.UTC),
+			expected: time.Date(2022, time.February, 21, 0, 0, 0, 0, Eastern),
+		},
+		{ // Explicit date
+			raw:      "Dec-27-21 03:20PM",
+			fetched:  time.Date(2022, time.January, 13, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2021, time.December, 27, 15, 20, 0, 0, Eastern),
+		},
+		{ // Today, fetched after midnight UTC
+			raw:      "Today 09:30PM",
+			fetched:  time.Date(2022, time.March, 2, 3, 0, 0, 0, time.UTC),
+			expected: time.Date(2022, time.March, 1, 21, 30, 0, 0, Eastern),
+		},
+	}
+
+	for _, v := range values {
+		date, err := ParseNewsDate(v.raw, v.fetched)
+		require.Nil(t, err)
+		require.True(t, v.expected.Equal(date), "%s: expected %v, got %v", v.raw, v.expected, date)
+	}
+
+	for _, raw := range []string{"", "Yesterday", "Feb-30", "Jan-02 25:00PM", "a b c"} {
+		_, err := ParseNewsDate(raw, time.Now())
+		require.NotNil(t, err, raw)
+	}
+}
+
+func TestParseCalendarDate(t *testing.T) {
+	date, err := ParseCalendarDate("Tue  Jan 18", "8:30 AM", 2022)
+	require.Nil(t, err)
+	require.Equal(t, "2022-01-18T08:30:00-05:00", date.Format(time.RFC3339))
+
+	date, err = ParseCalendarDate("Wed Jul 13", "8:30 AM", 2022)
+	require.Nil(t, err)
+	require.Equal(t, "2022-07-13T08:30:00-04:00", date.Format(time.RFC3339))
+
+	_, err = ParseCalendarDate("Wed Jul 13", "Tentative", 2022)
+	require.NotNil(t, err)
+}
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2022, time.July, 13, 8, 30, 0, 0, Eastern)
+	var ics bytes.Buffer
+	err := WriteICS(&ics, "Test", start, []ICSEvent{
+		{
+			UID:         ICSUID("20220713T0830", "CPI"),
+			Summary:     "CPI; m/m, y/y",
+			Description: strings.Repeat("Impact: critical\n", 6),
+			Start:       start,
+			End:         start.Add(15 * time.Minute),
+		},
+		{UID: "b", Summary: "AAPL earnings", Start: start, End: start.AddDate(0, 0, 1), AllDay: true},
+	})
+	require.Nil(t, err)
+
+	output := ics.String()
+	require.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\n"))
+	require.True(t, strings.HasSuffix(output, "END:VCALENDAR\r\n"))
+	require.Contains(t, output, "UID:20220713t0830-cpi@finviz.com\r\n")
+	require.Contains(t, output, "DTSTAMP:20220713T123000Z\r\n")
+	require.Contains(t, output, "DTSTART;TZID=America/New_York:20220713T083000\r\n")
+	require.Contains(t, output, `SUMMARY:CPI\; m/m\, y/y`+"\r\n")
+	require.Contains(t, output, "DTSTART;VALUE=DATE:20220713\r\nDTEND;VALUE=DATE:20220714\r\n")
+	require.Equal(t, 2, strings.Count(output, "BEGIN:VEVENT"))
+	for _, line := range strings.Split(output, "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+	}
+}